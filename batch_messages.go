@@ -0,0 +1,117 @@
+package feishu
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BatchMessageParam 批量消息操作的请求结构体，BatchMessageId 为 BatchSendMessagesRes.Data.MessageId
+type BatchMessageParam struct {
+	BatchMessageId string
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// GetBatchMessageProgressRes 查询批量消息整体进度的响应结构体
+type GetBatchMessageProgressRes struct {
+	ResponseCode
+	Data struct {
+		BatchMessageSendProgress   BatchMessageSendProgress   `json:"batch_message_send_progress"`
+		BatchMessageRecallProgress BatchMessageRecallProgress `json:"batch_message_recall_progress"`
+	} `json:"data"`
+}
+
+// BatchMessageSendProgress 批量消息发送进度
+type BatchMessageSendProgress struct {
+	ValidUserIdsCount   int64 `json:"valid_user_ids_count"`   // 有效的接收人数
+	SuccessUserIdsCount int64 `json:"success_user_ids_count"` // 发送成功的人数
+	ReadUserIdsCount    int64 `json:"read_user_ids_count"`    // 已读的人数
+}
+
+// PendingOrFailedUserIdsCount 尚未发送成功的人数，包含发送中与发送失败的接收人，发送完成后即为失败人数
+func (p BatchMessageSendProgress) PendingOrFailedUserIdsCount() int64 {
+	return p.ValidUserIdsCount - p.SuccessUserIdsCount
+}
+
+// BatchMessageRecallProgress 批量消息撤回进度
+type BatchMessageRecallProgress struct {
+	Recall      bool  `json:"recall"`       // 是否发起过撤回
+	RecallCount int64 `json:"recall_count"` // 已撤回的人数
+}
+
+// GetBatchMessageProgress 查询批量消息整体进度，发送完成前数据可能会变化
+func (c *Client) GetBatchMessageProgress(param BatchMessageParam) (*GetBatchMessageProgressRes, error) {
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/batch_messages/"+param.BatchMessageId+"/get_progress", nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data GetBatchMessageProgressRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// GetBatchMessageReadUserRes 查询批量消息推送和阅读人数的响应结构体
+type GetBatchMessageReadUserRes struct {
+	ResponseCode
+	Data struct {
+		ReadUser struct {
+			ReadCount  string `json:"read_count"`
+			TotalCount string `json:"total_count"`
+		} `json:"read_user"`
+	} `json:"data"`
+}
+
+// GetBatchMessageReadUser 查询批量消息推送和阅读人数，仅返回人数，不包含具体用户
+func (c *Client) GetBatchMessageReadUser(param BatchMessageParam) (*GetBatchMessageReadUserRes, error) {
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/batch_messages/"+param.BatchMessageId+"/read_user", nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data GetBatchMessageReadUserRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// DeleteBatchMessageRes 批量撤回消息的响应结构体
+type DeleteBatchMessageRes struct {
+	ResponseCode
+}
+
+// DeleteBatchMessage 批量撤回消息，撤回为异步执行，可通过 GetBatchMessageProgress 查询进度
+func (c *Client) DeleteBatchMessage(param BatchMessageParam) (*DeleteBatchMessageRes, error) {
+	request, _ := http.NewRequest(http.MethodDelete, ServerUrl+"/open-apis/im/v1/batch_messages/"+param.BatchMessageId, nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data DeleteBatchMessageRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
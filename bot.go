@@ -0,0 +1,79 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// 事件类型
+const (
+	EventTypeBotMenu = "application.bot.menu_v6" // 机器人自定义菜单
+)
+
+// 机器人激活状态
+const (
+	BotActivateStatusInit       = 0 // 初始化，租户待安装
+	BotActivateStatusActive     = 2 // 启用
+	BotActivateStatusDeactivate = 3 // 停用
+	BotActivateStatusUninstall  = 4 // 已卸载
+)
+
+// BotInfo 机器人信息
+type BotInfo struct {
+	ActivateStatus int      `json:"activate_status"`
+	AppName        string   `json:"app_name"`
+	AvatarUrl      string   `json:"avatar_url"`
+	IpWhiteList    []string `json:"ip_white_list"`
+	OpenId         string   `json:"open_id"`
+}
+
+// GetBotInfoRes 获取机器人信息的响应结构体
+type GetBotInfoRes struct {
+	ResponseCode
+	Bot BotInfo `json:"bot"`
+}
+
+// GetBotInfo 获取机器人信息，包括机器人的 open_id 与名称
+func (c *Client) GetBotInfo() (*GetBotInfoRes, error) {
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/bot/v3/info", nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data GetBotInfoRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// BotMenuEvent 机器人自定义菜单事件体
+type BotMenuEvent struct {
+	Operator  BotMenuEventOperator `json:"operator"`
+	EventKey  string               `json:"event_key"` // 菜单的事件 key，在开发者后台配置
+	Timestamp int64                `json:"timestamp"` // 毫秒级时间戳
+}
+
+type BotMenuEventOperator struct {
+	OperatorName string      `json:"operator_name"`
+	OperatorId   EventUserId `json:"operator_id"`
+}
+
+// OnBotMenu 注册机器人自定义菜单事件处理函数
+func (d *EventDispatcher) OnBotMenu(handler func(ctx context.Context, event *Event, data *BotMenuEvent) error) {
+	d.On(EventTypeBotMenu, func(ctx context.Context, event *Event) error {
+		var data BotMenuEvent
+		if err := json.Unmarshal(event.Event, &data); err != nil {
+			return err
+		}
+		return handler(ctx, event, &data)
+	})
+}
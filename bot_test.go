@@ -0,0 +1,49 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestBotMenuCommand(t *testing.T) {
+	var sent []SendMessagesParam
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open-apis/bot/v3/info":
+			_, _ = w.Write([]byte(`{"code":0,"msg":"ok","bot":{"activate_status":2,"app_name":"部署助手","open_id":"ou_bot"}}`))
+		case "/open-apis/im/v1/messages":
+			var param SendMessagesParam
+			_ = json.NewDecoder(r.Body).Decode(&param)
+			param.ReceiveIdType = r.URL.Query().Get("receive_id_type")
+			sent = append(sent, param)
+			_, _ = w.Write([]byte(`{"code":0,"msg":"ok"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+
+	router := NewCommandRouter(client)
+	if err := router.LoadBotInfo(); err != nil || router.BotOpenId != "ou_bot" {
+		t.Fatalf("LoadBotInfo() = %v, BotOpenId %s", err, router.BotOpenId)
+	}
+	router.Command(&Command{Name: "status", Handler: func(ctx *CommandContext) error {
+		return ctx.ReplyText("ok " + ctx.SenderId().OpenId + " " + ctx.ChatId())
+	}})
+	d := NewEventDispatcher()
+	router.Register(d)
+
+	data, _ := json.Marshal(BotMenuEvent{
+		Operator: BotMenuEventOperator{OperatorName: "张三", OperatorId: EventUserId{OpenId: "ou_user"}},
+		EventKey: "status",
+	})
+	event := &Event{Header: EventHeader{EventType: EventTypeBotMenu}, Event: data}
+	if err := d.Dispatch(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 1 || sent[0].ReceiveIdType != ReceiveIdTypeOpenId || sent[0].ReceiveId != "ou_user" || sent[0].Content != `{"text":"ok ou_user "}` {
+		t.Errorf("sent = %+v", sent)
+	}
+}
@@ -0,0 +1,804 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 卡片大小限制
+const (
+	CardMaxSize     = 30 * 1024 // 卡片 JSON 最大字节数
+	CardMaxElements = 50        // 单层元素最大数量
+	CardMaxActions  = 10        // 单个交互模块中的交互元素最大数量
+	CardMaxOptions  = 30        // 选择器、折叠按钮组的选项最大数量
+)
+
+// 卡片标题颜色
+const (
+	CardTemplateBlue      = "blue"
+	CardTemplateWathet    = "wathet"
+	CardTemplateTurquoise = "turquoise"
+	CardTemplateGreen     = "green"
+	CardTemplateYellow    = "yellow"
+	CardTemplateOrange    = "orange"
+	CardTemplateRed       = "red"
+	CardTemplateCarmine   = "carmine"
+	CardTemplateViolet    = "violet"
+	CardTemplatePurple    = "purple"
+	CardTemplateIndigo    = "indigo"
+	CardTemplateGrey      = "grey"
+)
+
+// 按钮类型
+const (
+	CardButtonDefault = "default"
+	CardButtonPrimary = "primary"
+	CardButtonDanger  = "danger"
+)
+
+// 卡片元素标签
+const (
+	CardTagPlainText      = "plain_text"
+	CardTagLarkMd         = "lark_md"
+	CardTagDiv            = "div"
+	CardTagMarkdown       = "markdown"
+	CardTagHr             = "hr"
+	CardTagImg            = "img"
+	CardTagNote           = "note"
+	CardTagColumnSet      = "column_set"
+	CardTagColumn         = "column"
+	CardTagAction         = "action"
+	CardTagButton         = "button"
+	CardTagSelectStatic   = "select_static"
+	CardTagSelectPerson   = "select_person"
+	CardTagOverflow       = "overflow"
+	CardTagDatePicker     = "date_picker"
+	CardTagPickerTime     = "picker_time"
+	CardTagPickerDatetime = "picker_datetime"
+)
+
+// Card 消息卡片
+type Card struct {
+	Config   *CardConfig                `json:"config,omitempty"`
+	Header   *CardHeader                `json:"header,omitempty"`
+	CardLink *CardUrl                   `json:"card_link,omitempty"`
+	Elements []CardElement              `json:"elements"`
+	Extra    map[string]json.RawMessage `json:"-"` // 未识别的顶层字段，如 i18n_elements、i18n_header，序列化时原样输出
+}
+
+func (*Card) MsgType() string { return MsgTypeInteractive }
+
+// CardConfig 卡片配置
+type CardConfig struct {
+	WideScreenMode bool `json:"wide_screen_mode"`
+	EnableForward  bool `json:"enable_forward"`
+	UpdateMulti    bool `json:"update_multi,omitempty"` // 共享卡片，更新后所有接收者可见
+}
+
+// CardHeader 卡片标题
+type CardHeader struct {
+	Title    CardText `json:"title"`
+	Template string   `json:"template,omitempty"`
+}
+
+// CardUrl 多端跳转链接
+type CardUrl struct {
+	Url        string `json:"url,omitempty"`
+	AndroidUrl string `json:"android_url,omitempty"`
+	IosUrl     string `json:"ios_url,omitempty"`
+	PcUrl      string `json:"pc_url,omitempty"`
+}
+
+// CardElement 卡片元素
+type CardElement interface {
+	Tag() string
+}
+
+// CardText 文本元素，Tag 为 plain_text 或 lark_md
+type CardText struct {
+	TagName string            `json:"tag"`
+	Content string            `json:"content"`
+	Lines   int               `json:"lines,omitempty"`
+	I18n    map[string]string `json:"i18n,omitempty"`
+}
+
+func (e *CardText) Tag() string { return e.TagName }
+
+// CardPlainText 纯文本
+func CardPlainText(content string) *CardText {
+	return &CardText{TagName: CardTagPlainText, Content: content}
+}
+
+// CardLarkMd lark_md 文本
+func CardLarkMd(content string) *CardText {
+	return &CardText{TagName: CardTagLarkMd, Content: content}
+}
+
+// CardField 内容模块中的字段
+type CardField struct {
+	IsShort bool      `json:"is_short"`
+	Text    *CardText `json:"text"`
+}
+
+// CardDiv 内容模块
+type CardDiv struct {
+	Text   *CardText   `json:"text,omitempty"`
+	Fields []CardField `json:"fields,omitempty"`
+	Extra  CardElement `json:"extra,omitempty"`
+}
+
+func (*CardDiv) Tag() string { return CardTagDiv }
+
+func (e *CardDiv) MarshalJSON() ([]byte, error) {
+	type alias CardDiv
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardMarkdown Markdown 模块
+type CardMarkdown struct {
+	Content   string             `json:"content"`
+	TextAlign string             `json:"text_align,omitempty"`
+	Href      map[string]CardUrl `json:"href,omitempty"`
+}
+
+func (*CardMarkdown) Tag() string { return CardTagMarkdown }
+
+func (e *CardMarkdown) MarshalJSON() ([]byte, error) {
+	type alias CardMarkdown
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardHr 分割线
+type CardHr struct{}
+
+func (*CardHr) Tag() string { return CardTagHr }
+
+func (e *CardHr) MarshalJSON() ([]byte, error) {
+	return marshalCardElement(e.Tag(), struct{}{})
+}
+
+// CardImg 图片
+type CardImg struct {
+	ImgKey       string    `json:"img_key"`
+	Alt          *CardText `json:"alt"`
+	Title        *CardText `json:"title,omitempty"`
+	Mode         string    `json:"mode,omitempty"`
+	CustomWidth  int       `json:"custom_width,omitempty"`
+	CompactWidth bool      `json:"compact_width,omitempty"`
+	Preview      *bool     `json:"preview,omitempty"`
+}
+
+func (*CardImg) Tag() string { return CardTagImg }
+
+func (e *CardImg) MarshalJSON() ([]byte, error) {
+	type alias CardImg
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardNote 备注，Elements 只能是文本或图片
+type CardNote struct {
+	Elements []CardElement `json:"elements"`
+}
+
+func (*CardNote) Tag() string { return CardTagNote }
+
+func (e *CardNote) MarshalJSON() ([]byte, error) {
+	type alias CardNote
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardColumnSet 多列布局
+type CardColumnSet struct {
+	FlexMode          string               `json:"flex_mode,omitempty"`
+	BackgroundStyle   string               `json:"background_style,omitempty"`
+	HorizontalSpacing string               `json:"horizontal_spacing,omitempty"`
+	Columns           []*CardColumn        `json:"columns"`
+	Action            *CardColumnSetAction `json:"action,omitempty"`
+}
+
+// CardColumnSetAction 多列布局的点击跳转
+type CardColumnSetAction struct {
+	MultiUrl *CardUrl `json:"multi_url,omitempty"`
+}
+
+func (*CardColumnSet) Tag() string { return CardTagColumnSet }
+
+func (e *CardColumnSet) MarshalJSON() ([]byte, error) {
+	type alias CardColumnSet
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardColumn 列
+type CardColumn struct {
+	Width         string        `json:"width,omitempty"`
+	Weight        int           `json:"weight,omitempty"`
+	VerticalAlign string        `json:"vertical_align,omitempty"`
+	Elements      []CardElement `json:"elements"`
+}
+
+func (*CardColumn) Tag() string { return CardTagColumn }
+
+func (e *CardColumn) MarshalJSON() ([]byte, error) {
+	type alias CardColumn
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardActionBlock 交互模块
+type CardActionBlock struct {
+	Actions []CardElement `json:"actions"`
+	Layout  string        `json:"layout,omitempty"`
+}
+
+func (*CardActionBlock) Tag() string { return CardTagAction }
+
+func (e *CardActionBlock) MarshalJSON() ([]byte, error) {
+	type alias CardActionBlock
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardConfirm 二次确认弹框
+type CardConfirm struct {
+	Title *CardText `json:"title"`
+	Text  *CardText `json:"text"`
+}
+
+// CardOption 选项
+type CardOption struct {
+	Text     *CardText `json:"text,omitempty"`
+	Value    string    `json:"value"`
+	Url      string    `json:"url,omitempty"`
+	MultiUrl *CardUrl  `json:"multi_url,omitempty"`
+}
+
+// CardButton 按钮
+type CardButton struct {
+	Text     *CardText              `json:"text"`
+	Url      string                 `json:"url,omitempty"`
+	MultiUrl *CardUrl               `json:"multi_url,omitempty"`
+	Type     string                 `json:"type,omitempty"`
+	Value    map[string]interface{} `json:"value,omitempty"`
+	Confirm  *CardConfirm           `json:"confirm,omitempty"`
+}
+
+func (*CardButton) Tag() string { return CardTagButton }
+
+func (e *CardButton) MarshalJSON() ([]byte, error) {
+	type alias CardButton
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// NewCardButton 创建按钮
+func NewCardButton(text, buttonType string) *CardButton {
+	return &CardButton{Text: CardPlainText(text), Type: buttonType}
+}
+
+// WithValue 设置回传参数
+func (e *CardButton) WithValue(value map[string]interface{}) *CardButton {
+	e.Value = value
+	return e
+}
+
+// WithUrl 设置跳转链接
+func (e *CardButton) WithUrl(url string) *CardButton {
+	e.Url = url
+	return e
+}
+
+// WithConfirm 设置二次确认弹框
+func (e *CardButton) WithConfirm(title, text string) *CardButton {
+	e.Confirm = &CardConfirm{Title: CardPlainText(title), Text: CardPlainText(text)}
+	return e
+}
+
+// CardSelectStatic 单选下拉菜单
+type CardSelectStatic struct {
+	Placeholder   *CardText              `json:"placeholder,omitempty"`
+	InitialOption string                 `json:"initial_option,omitempty"`
+	Options       []CardOption           `json:"options"`
+	Value         map[string]interface{} `json:"value,omitempty"`
+	Confirm       *CardConfirm           `json:"confirm,omitempty"`
+}
+
+func (*CardSelectStatic) Tag() string { return CardTagSelectStatic }
+
+func (e *CardSelectStatic) MarshalJSON() ([]byte, error) {
+	type alias CardSelectStatic
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardSelectPerson 选人下拉菜单，Options 的 Value 为 open_id，为空时可选择群内成员
+type CardSelectPerson struct {
+	Placeholder   *CardText              `json:"placeholder,omitempty"`
+	InitialOption string                 `json:"initial_option,omitempty"`
+	Options       []CardOption           `json:"options,omitempty"`
+	Value         map[string]interface{} `json:"value,omitempty"`
+	Confirm       *CardConfirm           `json:"confirm,omitempty"`
+}
+
+func (*CardSelectPerson) Tag() string { return CardTagSelectPerson }
+
+func (e *CardSelectPerson) MarshalJSON() ([]byte, error) {
+	type alias CardSelectPerson
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardOverflow 折叠按钮组
+type CardOverflow struct {
+	Options []CardOption           `json:"options"`
+	Value   map[string]interface{} `json:"value,omitempty"`
+	Confirm *CardConfirm           `json:"confirm,omitempty"`
+}
+
+func (*CardOverflow) Tag() string { return CardTagOverflow }
+
+func (e *CardOverflow) MarshalJSON() ([]byte, error) {
+	type alias CardOverflow
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// CardDatePicker 日期、时间、日期时间选择器，TagName 为 date_picker、picker_time 或 picker_datetime
+type CardDatePicker struct {
+	TagName         string                 `json:"-"`
+	InitialDate     string                 `json:"initial_date,omitempty"`     // yyyy-MM-dd
+	InitialTime     string                 `json:"initial_time,omitempty"`     // HH:mm
+	InitialDatetime string                 `json:"initial_datetime,omitempty"` // yyyy-MM-dd HH:mm
+	Placeholder     *CardText              `json:"placeholder,omitempty"`
+	Value           map[string]interface{} `json:"value,omitempty"`
+	Confirm         *CardConfirm           `json:"confirm,omitempty"`
+}
+
+func (e *CardDatePicker) Tag() string {
+	if e.TagName == "" {
+		return CardTagDatePicker
+	}
+	return e.TagName
+}
+
+func (e *CardDatePicker) MarshalJSON() ([]byte, error) {
+	type alias CardDatePicker
+	return marshalCardElement(e.Tag(), (*alias)(e))
+}
+
+// marshalCardElement 序列化卡片元素并写入 tag
+func marshalCardElement(tag string, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	prefix := `{"tag":"` + tag + `"`
+	if len(b) <= 2 {
+		return []byte(prefix + "}"), nil
+	}
+	return append([]byte(prefix+","), b[1:]...), nil
+}
+
+// CardRawElement 未识别的卡片元素，按原始 JSON 序列化
+type CardRawElement struct {
+	TagName string
+	Raw     json.RawMessage
+}
+
+func (e *CardRawElement) Tag() string { return e.TagName }
+
+func (e *CardRawElement) MarshalJSON() ([]byte, error) {
+	return e.Raw, nil
+}
+
+// UnmarshalCardElement 按 tag 反序列化卡片元素，未识别的 tag 返回 *CardRawElement
+func UnmarshalCardElement(data []byte) (CardElement, error) {
+	var head struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+	var e CardElement
+	switch head.Tag {
+	case CardTagPlainText, CardTagLarkMd:
+		e = &CardText{}
+	case CardTagDiv:
+		e = &CardDiv{}
+	case CardTagMarkdown:
+		e = &CardMarkdown{}
+	case CardTagHr:
+		e = &CardHr{}
+	case CardTagImg:
+		e = &CardImg{}
+	case CardTagNote:
+		e = &CardNote{}
+	case CardTagColumnSet:
+		e = &CardColumnSet{}
+	case CardTagColumn:
+		e = &CardColumn{}
+	case CardTagAction:
+		e = &CardActionBlock{}
+	case CardTagButton:
+		e = &CardButton{}
+	case CardTagSelectStatic:
+		e = &CardSelectStatic{}
+	case CardTagSelectPerson:
+		e = &CardSelectPerson{}
+	case CardTagOverflow:
+		e = &CardOverflow{}
+	case CardTagDatePicker, CardTagPickerTime, CardTagPickerDatetime:
+		e = &CardDatePicker{TagName: head.Tag}
+	default:
+		return &CardRawElement{TagName: head.Tag, Raw: append(json.RawMessage(nil), data...)}, nil
+	}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func unmarshalCardElements(raw []json.RawMessage) ([]CardElement, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	elements := make([]CardElement, 0, len(raw))
+	for _, data := range raw {
+		e, err := UnmarshalCardElement(data)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, e)
+	}
+	return elements, nil
+}
+
+// cardKnownFields Card 中已定义的顶层字段
+var cardKnownFields = map[string]bool{"config": true, "header": true, "card_link": true, "elements": true}
+
+func (c *Card) MarshalJSON() ([]byte, error) {
+	type alias Card
+	b, err := json.Marshal((*alias)(c))
+	if err != nil {
+		return nil, err
+	}
+	return appendCardExtra(b, c.Extra, cardKnownFields)
+}
+
+// appendCardExtra 将未识别的顶层字段追加到已序列化的 JSON 对象末尾，跳过 known 中的字段
+func appendCardExtra(b []byte, extra map[string]json.RawMessage, known map[string]bool) ([]byte, error) {
+	fields := make(map[string]json.RawMessage, len(extra))
+	for k, v := range extra {
+		if !known[k] {
+			fields[k] = v
+		}
+	}
+	if len(fields) == 0 {
+		return b, nil
+	}
+	e, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(b[:len(b)-1], ','), e[1:]...), nil
+}
+
+func (c *Card) UnmarshalJSON(data []byte) (err error) {
+	type alias Card
+	v := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{alias: (*alias)(c)}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	c.Extra = nil
+	for k, raw := range fields {
+		if cardKnownFields[k] {
+			continue
+		}
+		if c.Extra == nil {
+			c.Extra = make(map[string]json.RawMessage)
+		}
+		c.Extra[k] = raw
+	}
+	c.Elements, err = unmarshalCardElements(v.Elements)
+	return err
+}
+
+func (e *CardDiv) UnmarshalJSON(data []byte) (err error) {
+	type alias CardDiv
+	v := struct {
+		*alias
+		Extra json.RawMessage `json:"extra"`
+	}{alias: (*alias)(e)}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Extra) > 0 && string(v.Extra) != "null" {
+		e.Extra, err = UnmarshalCardElement(v.Extra)
+	}
+	return err
+}
+
+func (e *CardNote) UnmarshalJSON(data []byte) (err error) {
+	type alias CardNote
+	v := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{alias: (*alias)(e)}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	e.Elements, err = unmarshalCardElements(v.Elements)
+	return err
+}
+
+func (e *CardColumn) UnmarshalJSON(data []byte) (err error) {
+	type alias CardColumn
+	v := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{alias: (*alias)(e)}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	e.Elements, err = unmarshalCardElements(v.Elements)
+	return err
+}
+
+func (e *CardActionBlock) UnmarshalJSON(data []byte) (err error) {
+	type alias CardActionBlock
+	v := struct {
+		*alias
+		Actions []json.RawMessage `json:"actions"`
+	}{alias: (*alias)(e)}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	e.Actions, err = unmarshalCardElements(v.Actions)
+	return err
+}
+
+// CardValidationError 卡片校验错误
+type CardValidationError struct {
+	Path string
+	Msg  string
+}
+
+func (e *CardValidationError) Error() string {
+	return fmt.Sprintf("feishu: invalid card at %s: %s", e.Path, e.Msg)
+}
+
+// 各容器允许的子元素
+var (
+	cardModuleTags = map[string]bool{
+		CardTagDiv: true, CardTagMarkdown: true, CardTagHr: true, CardTagImg: true,
+		CardTagNote: true, CardTagColumnSet: true, CardTagAction: true,
+	}
+	cardColumnTags = map[string]bool{
+		CardTagDiv: true, CardTagMarkdown: true, CardTagHr: true, CardTagImg: true,
+		CardTagNote: true, CardTagAction: true,
+	}
+	cardInteractiveTags = map[string]bool{
+		CardTagButton: true, CardTagSelectStatic: true, CardTagSelectPerson: true, CardTagOverflow: true,
+		CardTagDatePicker: true, CardTagPickerTime: true, CardTagPickerDatetime: true,
+	}
+	cardExtraTags = map[string]bool{
+		CardTagButton: true, CardTagSelectStatic: true, CardTagSelectPerson: true, CardTagOverflow: true,
+		CardTagDatePicker: true, CardTagPickerTime: true, CardTagPickerDatetime: true, CardTagImg: true,
+	}
+	cardNoteTags = map[string]bool{
+		CardTagPlainText: true, CardTagLarkMd: true, CardTagImg: true,
+	}
+)
+
+// Validate 校验元素嵌套关系与大小限制
+func (c *Card) Validate() error {
+	if c.Header != nil && c.Header.Title.Content == "" {
+		return &CardValidationError{Path: "header.title", Msg: "content is empty"}
+	}
+	if err := validateCardElements("elements", c.Elements, cardModuleTags); err != nil {
+		return err
+	}
+	if err := c.validateI18n(); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if len(b) > CardMaxSize {
+		return &CardValidationError{Path: "card", Msg: fmt.Sprintf("size %d exceeds %d bytes", len(b), CardMaxSize)}
+	}
+	return nil
+}
+
+// validateI18n 校验多语言卡片的 i18n_header 与 i18n_elements
+func (c *Card) validateI18n() error {
+	if raw, ok := c.Extra["i18n_header"]; ok {
+		var headers map[string]CardHeader
+		if err := json.Unmarshal(raw, &headers); err != nil {
+			return &CardValidationError{Path: "i18n_header", Msg: err.Error()}
+		}
+		for lang, header := range headers {
+			if header.Title.Content == "" {
+				return &CardValidationError{Path: "i18n_header." + lang + ".title", Msg: "content is empty"}
+			}
+		}
+	}
+	if raw, ok := c.Extra["i18n_elements"]; ok {
+		var i18n map[string][]json.RawMessage
+		if err := json.Unmarshal(raw, &i18n); err != nil {
+			return &CardValidationError{Path: "i18n_elements", Msg: err.Error()}
+		}
+		for lang, data := range i18n {
+			path := "i18n_elements." + lang
+			elements, err := unmarshalCardElements(data)
+			if err != nil {
+				return &CardValidationError{Path: path, Msg: err.Error()}
+			}
+			if err = validateCardElements(path, elements, cardModuleTags); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateCardElements(path string, elements []CardElement, allowed map[string]bool) error {
+	if len(elements) > CardMaxElements {
+		return &CardValidationError{Path: path, Msg: fmt.Sprintf("%d elements exceeds %d", len(elements), CardMaxElements)}
+	}
+	for i, e := range elements {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		if e == nil {
+			return &CardValidationError{Path: p, Msg: "element is nil"}
+		}
+		if !allowed[e.Tag()] {
+			return &CardValidationError{Path: p, Msg: e.Tag() + " is not allowed here"}
+		}
+		if err := validateCardElement(p, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCardElement(path string, element CardElement) error {
+	switch e := element.(type) {
+	case *CardDiv:
+		if e.Text == nil && len(e.Fields) == 0 {
+			return &CardValidationError{Path: path, Msg: "div requires text or fields"}
+		}
+		if e.Extra != nil {
+			if !cardExtraTags[e.Extra.Tag()] {
+				return &CardValidationError{Path: path + ".extra", Msg: e.Extra.Tag() + " is not allowed here"}
+			}
+			return validateCardElement(path+".extra", e.Extra)
+		}
+	case *CardImg:
+		if e.ImgKey == "" || e.Alt == nil {
+			return &CardValidationError{Path: path, Msg: "img requires img_key and alt"}
+		}
+	case *CardNote:
+		if len(e.Elements) == 0 {
+			return &CardValidationError{Path: path, Msg: "note requires elements"}
+		}
+		return validateCardElements(path+".elements", e.Elements, cardNoteTags)
+	case *CardColumnSet:
+		for i, column := range e.Columns {
+			p := fmt.Sprintf("%s.columns[%d]", path, i)
+			if column == nil {
+				return &CardValidationError{Path: p, Msg: "column is nil"}
+			}
+			if err := validateCardElements(p+".elements", column.Elements, cardColumnTags); err != nil {
+				return err
+			}
+		}
+	case *CardActionBlock:
+		if len(e.Actions) == 0 || len(e.Actions) > CardMaxActions {
+			return &CardValidationError{Path: path, Msg: fmt.Sprintf("action requires 1 to %d actions", CardMaxActions)}
+		}
+		return validateCardElements(path+".actions", e.Actions, cardInteractiveTags)
+	case *CardButton:
+		if e.Text == nil {
+			return &CardValidationError{Path: path, Msg: "button requires text"}
+		}
+	case *CardSelectStatic:
+		return validateCardOptions(path, e.Options, true)
+	case *CardSelectPerson:
+		return validateCardOptions(path, e.Options, false)
+	case *CardOverflow:
+		return validateCardOptions(path, e.Options, true)
+	}
+	return nil
+}
+
+func validateCardOptions(path string, options []CardOption, required bool) error {
+	if required && len(options) == 0 {
+		return &CardValidationError{Path: path, Msg: "options is empty"}
+	}
+	if len(options) > CardMaxOptions {
+		return &CardValidationError{Path: path, Msg: fmt.Sprintf("%d options exceeds %d", len(options), CardMaxOptions)}
+	}
+	return nil
+}
+
+// CardBuilder 消息卡片构造器
+type CardBuilder struct {
+	card *Card
+}
+
+// NewCardBuilder 创建消息卡片构造器，默认宽屏且允许转发
+func NewCardBuilder() *CardBuilder {
+	return &CardBuilder{card: &Card{
+		Config:   &CardConfig{WideScreenMode: true, EnableForward: true},
+		Elements: []CardElement{},
+	}}
+}
+
+// Config 设置卡片配置
+func (b *CardBuilder) Config(config CardConfig) *CardBuilder {
+	b.card.Config = &config
+	return b
+}
+
+// Header 设置卡片标题与颜色
+func (b *CardBuilder) Header(title, template string) *CardBuilder {
+	b.card.Header = &CardHeader{Title: *CardPlainText(title), Template: template}
+	return b
+}
+
+// Link 设置卡片整体跳转链接
+func (b *CardBuilder) Link(link CardUrl) *CardBuilder {
+	b.card.CardLink = &link
+	return b
+}
+
+// Element 追加任意元素
+func (b *CardBuilder) Element(elements ...CardElement) *CardBuilder {
+	b.card.Elements = append(b.card.Elements, elements...)
+	return b
+}
+
+// Markdown 追加 Markdown 模块
+func (b *CardBuilder) Markdown(content string) *CardBuilder {
+	return b.Element(&CardMarkdown{Content: content})
+}
+
+// Div 追加内容模块，text 可以为 nil
+func (b *CardBuilder) Div(text *CardText, fields ...CardField) *CardBuilder {
+	return b.Element(&CardDiv{Text: text, Fields: fields})
+}
+
+// Hr 追加分割线
+func (b *CardBuilder) Hr() *CardBuilder {
+	return b.Element(&CardHr{})
+}
+
+// Img 追加图片
+func (b *CardBuilder) Img(imgKey, alt string) *CardBuilder {
+	return b.Element(&CardImg{ImgKey: imgKey, Alt: CardPlainText(alt)})
+}
+
+// Note 追加备注
+func (b *CardBuilder) Note(elements ...CardElement) *CardBuilder {
+	return b.Element(&CardNote{Elements: elements})
+}
+
+// Columns 追加多列布局
+func (b *CardBuilder) Columns(columns ...*CardColumn) *CardBuilder {
+	return b.Element(&CardColumnSet{FlexMode: "none", Columns: columns})
+}
+
+// Actions 追加交互模块
+func (b *CardBuilder) Actions(actions ...CardElement) *CardBuilder {
+	return b.Element(&CardActionBlock{Actions: actions})
+}
+
+// Build 校验并获取卡片
+func (b *CardBuilder) Build() (*Card, error) {
+	if err := b.card.Validate(); err != nil {
+		return nil, err
+	}
+	return b.card, nil
+}
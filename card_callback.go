@@ -0,0 +1,307 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// 事件类型
+const (
+	EventTypeCardActionTrigger = "card.action.trigger" // 卡片回传交互 (2.0)
+)
+
+// CardAction 消息卡片交互回调
+//
+// 兼容卡片请求网址 (1.0) 与 card.action.trigger (2.0) 两种回调格式
+type CardAction struct {
+	OpenId        string           `json:"open_id"`
+	UserId        string           `json:"user_id"`
+	UnionId       string           `json:"union_id"`
+	OpenMessageId string           `json:"open_message_id"`
+	OpenChatId    string           `json:"open_chat_id"`
+	TenantKey     string           `json:"tenant_key"`
+	Token         string           `json:"token"` // 用于 InteractiveV1CardUpdate 延迟更新卡片
+	Action        CardActionAction `json:"action"`
+
+	// Schema 回调格式，2.0 时才支持 toast
+	Schema string `json:"-"`
+}
+
+type CardActionAction struct {
+	Value     map[string]interface{} `json:"value"`
+	Tag       string                 `json:"tag"`
+	Option    string                 `json:"option"`
+	Options   []string               `json:"options"`
+	Timezone  string                 `json:"timezone"`
+	Name      string                 `json:"name"`
+	FormValue map[string]interface{} `json:"form_value"`
+}
+
+// ValueString 获取回传参数中的字符串
+func (a *CardActionAction) ValueString(key string) string {
+	if v, ok := a.Value[key]; ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return fmt.Sprintf("%v", v)
+	}
+	return ""
+}
+
+// 弹出提示类型
+const (
+	ToastInfo    = "info"
+	ToastSuccess = "success"
+	ToastError   = "error"
+	ToastWarning = "warning"
+)
+
+// CardToast 弹出提示
+type CardToast struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// CardActionResponse 卡片回调的同步响应
+//
+// Card 不为空时替换原卡片；Toast 仅在 2.0 回调中生效
+type CardActionResponse struct {
+	Card  interface{}
+	Toast *CardToast
+}
+
+// CardActionHandlerFunc 卡片交互处理函数，返回 nil 表示不更新卡片
+type CardActionHandlerFunc func(ctx context.Context, action *CardAction) (*CardActionResponse, error)
+
+// CardActionHandler 消息卡片回调 http.Handler
+//
+// 按回传参数 action.value[RouteKey] 路由到处理函数
+type CardActionHandler struct {
+	Crypto   *Crypto
+	RouteKey string
+
+	mu       sync.RWMutex
+	handlers map[string]CardActionHandlerFunc
+	fallback CardActionHandlerFunc
+}
+
+// NewCardActionHandler 创建卡片回调处理器，默认按 action.value["action"] 路由
+func NewCardActionHandler(crypto *Crypto) *CardActionHandler {
+	return &CardActionHandler{
+		Crypto:   crypto,
+		RouteKey: "action",
+		handlers: map[string]CardActionHandlerFunc{},
+	}
+}
+
+// Handle 注册处理函数
+func (h *CardActionHandler) Handle(value string, handler CardActionHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[value] = handler
+}
+
+// HandleDefault 注册未匹配路由时的处理函数
+func (h *CardActionHandler) HandleDefault(handler CardActionHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = handler
+}
+
+// Dispatch 路由卡片交互到处理函数
+func (h *CardActionHandler) Dispatch(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+	h.mu.RLock()
+	handler, ok := h.handlers[action.Action.ValueString(h.RouteKey)]
+	if !ok {
+		handler = h.fallback
+	}
+	h.mu.RUnlock()
+
+	if handler == nil {
+		if Logger != nil {
+			Logger.Printf("unhandled card action %v", action.Action.Value)
+		}
+		return nil, nil
+	}
+	return handler(ctx, action)
+}
+
+// ParseCardAction 解析卡片回调请求体
+//
+// 返回值 challenge 不为空时表示这是一次 url_verification 请求
+func ParseCardAction(crypto *Crypto, body []byte) (action *CardAction, challenge string, err error) {
+	var encrypted struct {
+		Encrypt string `json:"encrypt"`
+	}
+	if err = json.Unmarshal(body, &encrypted); err != nil {
+		return
+	}
+	if encrypted.Encrypt == "" && crypto != nil && crypto.EncryptKey != "" {
+		// 配置 EncryptKey 后 1.0 与 2.0 回调均需加密，明文请求视为伪造
+		err = ErrEventNotEncrypted
+		return
+	}
+	if encrypted.Encrypt != "" {
+		if crypto == nil || crypto.EncryptKey == "" {
+			err = errors.New("feishu: encrypted card action without encrypt key")
+			return
+		}
+		body, err = crypto.GetDecryptMsg(encrypted.Encrypt)
+		if err != nil {
+			return
+		}
+	}
+
+	var raw struct {
+		Schema    string      `json:"schema"`
+		Header    EventHeader `json:"header"`
+		Type      string      `json:"type"`
+		Token     string      `json:"token"`
+		Challenge string      `json:"challenge"`
+	}
+	if err = json.Unmarshal(body, &raw); err != nil {
+		err = fmt.Errorf("feishu: unmarshal card action: %w", err)
+		return
+	}
+
+	if raw.Type == "url_verification" {
+		if crypto != nil && crypto.VerificationToken != "" && raw.Token != crypto.VerificationToken {
+			err = ErrEventTokenMismatch
+			return
+		}
+		challenge = raw.Challenge
+		return
+	}
+
+	action = &CardAction{}
+	if raw.Schema == "" {
+		err = json.Unmarshal(body, action)
+		return
+	}
+
+	if crypto != nil && crypto.VerificationToken != "" && raw.Header.Token != crypto.VerificationToken {
+		err = ErrEventTokenMismatch
+		return
+	}
+	if raw.Header.EventType != EventTypeCardActionTrigger {
+		err = fmt.Errorf("feishu: unexpected event type %s", raw.Header.EventType)
+		return
+	}
+
+	var v2 struct {
+		Event struct {
+			Operator struct {
+				TenantKey string `json:"tenant_key"`
+				EventUserId
+			} `json:"operator"`
+			Token   string           `json:"token"`
+			Action  CardActionAction `json:"action"`
+			Context struct {
+				OpenMessageId string `json:"open_message_id"`
+				OpenChatId    string `json:"open_chat_id"`
+			} `json:"context"`
+		} `json:"event"`
+	}
+	if err = json.Unmarshal(body, &v2); err != nil {
+		return
+	}
+	action = &CardAction{
+		OpenId:        v2.Event.Operator.OpenId,
+		UserId:        v2.Event.Operator.UserId,
+		UnionId:       v2.Event.Operator.UnionId,
+		OpenMessageId: v2.Event.Context.OpenMessageId,
+		OpenChatId:    v2.Event.Context.OpenChatId,
+		TenantKey:     v2.Event.Operator.TenantKey,
+		Token:         v2.Event.Token,
+		Action:        v2.Event.Action,
+		Schema:        raw.Schema,
+	}
+	return
+}
+
+// MarshalCardActionResponse 按回调格式序列化响应
+//
+// 1.0 回调直接返回卡片内容，2.0 回调返回 toast 与 raw 类型的卡片
+func MarshalCardActionResponse(action *CardAction, res *CardActionResponse) ([]byte, error) {
+	if res == nil {
+		return []byte(`{}`), nil
+	}
+
+	if action.Schema == "" {
+		if res.Card == nil {
+			return []byte(`{}`), nil
+		}
+		return json.Marshal(res.Card)
+	}
+
+	body := map[string]interface{}{}
+	if res.Toast != nil {
+		body["toast"] = res.Toast
+	}
+	if res.Card != nil {
+		body["card"] = map[string]interface{}{
+			"type": "raw",
+			"data": res.Card,
+		}
+	}
+	return json.Marshal(body)
+}
+
+// ServeHTTP 处理卡片回调
+func (h *CardActionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	action, challenge, err := ParseCardAction(h.Crypto, body)
+	if err != nil {
+		if Logger != nil {
+			Logger.Printf("ParseCardAction error %s", err)
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeApplicationJson)
+	if action == nil {
+		resp, _ := json.Marshal(map[string]string{"challenge": challenge})
+		_, _ = w.Write(resp)
+		return
+	}
+
+	// 1.0 回调使用 VerificationToken 签名，未配置 VerificationToken 时无法校验，直接拒绝；
+	// 2.0 回调与事件回调一样使用 EncryptKey 签名
+	if h.Crypto != nil {
+		verified := h.Crypto.VerifySignature(r.Header, body)
+		if action.Schema == "" {
+			verified = h.Crypto.VerificationToken != "" && h.Crypto.VerifyCardSignature(r.Header, body)
+		}
+		if !verified {
+			http.Error(w, ErrEventSignature.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	res, err := h.Dispatch(r.Context(), action)
+	if err != nil {
+		if Logger != nil {
+			Logger.Printf("card action %s error %s", action.OpenMessageId, err)
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := MarshalCardActionResponse(action, res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(resp)
+}
@@ -0,0 +1,144 @@
+package feishu
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCardActionHandler(t *testing.T) {
+	handler := NewCardActionHandler(&Crypto{VerificationToken: "vt"})
+	handler.Handle("approve", func(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+		return &CardActionResponse{
+			Card:  map[string]string{"approved_by": action.OpenId},
+			Toast: &CardToast{Type: ToastSuccess, Content: "ok"},
+		}, nil
+	})
+
+	sign := func(body string) http.Header {
+		h := sha1.Sum([]byte("1" + "n" + "vt" + body))
+		header := http.Header{}
+		header.Set("X-Lark-Request-Timestamp", "1")
+		header.Set("X-Lark-Request-Nonce", "n")
+		header.Set("X-Lark-Signature", hex.EncodeToString(h[:]))
+		return header
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		header   http.Header
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "challenge",
+			body:     `{"challenge":"c1","token":"vt","type":"url_verification"}`,
+			header:   http.Header{},
+			wantCode: http.StatusOK,
+			wantBody: `{"challenge":"c1"}`,
+		},
+		{
+			name:     "v1",
+			body:     `{"open_id":"ou_1","token":"t","action":{"value":{"action":"approve"},"tag":"button"}}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"approved_by":"ou_1"}`,
+		},
+		{
+			name:     "v1 bad signature",
+			body:     `{"open_id":"ou_1","action":{"value":{"action":"approve"}}}`,
+			header:   http.Header{"X-Lark-Signature": []string{"bad"}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "v1 unrouted",
+			body:     `{"open_id":"ou_1","action":{"value":{"action":"reject"}}}`,
+			wantCode: http.StatusOK,
+			wantBody: `{}`,
+		},
+		{
+			name:     "v2",
+			body:     `{"schema":"2.0","header":{"event_type":"card.action.trigger","token":"vt"},"event":{"operator":{"open_id":"ou_2"},"action":{"value":{"action":"approve"}},"context":{"open_message_id":"om_1"}}}`,
+			header:   http.Header{},
+			wantCode: http.StatusOK,
+			wantBody: `{"card":{"data":{"approved_by":"ou_2"},"type":"raw"},"toast":{"type":"success","content":"ok"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.header != nil {
+				r.Header = tt.header
+			} else {
+				r.Header = sign(tt.body)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d, body %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestCardActionHandlerEncrypted(t *testing.T) {
+	handler := NewCardActionHandler(&Crypto{EncryptKey: "test key"})
+	var handled int
+	handler.Handle("approve", func(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+		handled++
+		return nil, nil
+	})
+
+	v2 := `{"schema":"2.0","header":{"event_type":"card.action.trigger"},"event":{"operator":{"open_id":"ou_2"},"action":{"value":{"action":"approve"}}}}`
+	encrypted := testEncrypt("test key", v2)
+	v1 := `{"open_id":"ou_x","open_message_id":"om_1","action":{"value":{"action":"approve"}}}`
+	tests := []struct {
+		name     string
+		body     string
+		sign     bool
+		wantCode int
+	}{
+		{"forged unsigned plaintext", v2, false, http.StatusBadRequest},
+		{"forged signed plaintext", v2, true, http.StatusBadRequest},
+		{"forged unsigned v1 plaintext", v1, false, http.StatusBadRequest},
+		{"unsigned encrypted v1", testEncrypt("test key", v1), false, http.StatusUnauthorized},
+		{"unsigned encrypted", encrypted, false, http.StatusUnauthorized},
+		{"signed encrypted", encrypted, true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+		if tt.sign {
+			testSign(r, "test key", tt.body)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: response %d %s", tt.name, w.Code, w.Body.String())
+		}
+	}
+	if handled != 1 {
+		t.Errorf("handled %d actions, want 1", handled)
+	}
+}
+
+func TestCardActionHandlerWithoutVerificationToken(t *testing.T) {
+	handler := NewCardActionHandler(&Crypto{})
+	handler.Handle("approve", func(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+		t.Error("forged v1 card action handled")
+		return nil, nil
+	})
+
+	body := `{"open_id":"ou_x","open_message_id":"om_1","action":{"value":{"action":"approve"}}}`
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("response %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,188 @@
+package feishu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+var ErrCardTemplateNotFound = errors.New("feishu: card template not found")
+
+// CardTemplates 卡片模板注册表，使用 text/template 渲染卡片 JSON
+//
+// 模板中字符串需通过 json 函数输出，lark_md 与 markdown 内容先经 md 函数转义：
+//
+//	{"tag": "markdown", "content": {{.Reason | md | json}}}
+//
+// 可用函数：json 输出 JSON 值，md 转义 lark_md 特殊字符，default 在值为空时返回默认值，
+// join 拼接字符串切片，comma 在 range 中非首个元素前输出逗号
+type CardTemplates struct {
+	DefaultLang string // 指定语言没有模板时使用的语言，默认 zh_cn
+
+	mu        sync.RWMutex
+	partials  map[string]string
+	texts     map[string]map[string]string // name => lang => text
+	templates map[string]map[string]*template.Template
+}
+
+// NewCardTemplates 创建卡片模板注册表
+func NewCardTemplates() *CardTemplates {
+	return &CardTemplates{
+		DefaultLang: LangZhCn,
+		partials:    make(map[string]string),
+		texts:       make(map[string]map[string]string),
+		templates:   make(map[string]map[string]*template.Template),
+	}
+}
+
+// CardTemplateFuncs 卡片模板中可用的函数
+var CardTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"md": CardEscapeMd,
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+	"join": strings.Join,
+	"comma": func(i int) string {
+		if i > 0 {
+			return ","
+		}
+		return ""
+	},
+}
+
+// cardMdEscaper lark_md 中需要转义的字符
+var cardMdEscaper = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+	"*", "&#42;", "_", "&#95;", "~", "&#126;", "`", "&#96;", "[", "&#91;", "]", "&#93;",
+)
+
+// CardEscapeMd 转义 lark_md 与 markdown 内容中的特殊字符，使其按原文展示
+func CardEscapeMd(s string) string {
+	return cardMdEscaper.Replace(s)
+}
+
+// Partial 注册可复用的片段，模板中通过 {{template "name" .}} 引用
+func (t *CardTemplates) Partial(name, text string) error {
+	if _, err := template.New(name).Funcs(CardTemplateFuncs).Parse(text); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	old, exists := t.partials[name]
+	t.partials[name] = text
+	if err := t.compileAll(); err != nil {
+		if exists {
+			t.partials[name] = old
+		} else {
+			delete(t.partials, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Register 注册模板，lang 为 zh_cn、en_us、ja_jp 等语言
+func (t *CardTemplates) Register(name, lang, text string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tmpl, err := t.compile(name, text)
+	if err != nil {
+		return err
+	}
+	if t.texts[name] == nil {
+		t.texts[name] = make(map[string]string)
+		t.templates[name] = make(map[string]*template.Template)
+	}
+	t.texts[name][lang] = text
+	t.templates[name][lang] = tmpl
+	return nil
+}
+
+// Render 渲染并校验卡片，指定语言没有模板时依次使用 DefaultLang 与任意已注册的语言
+//
+// 返回的卡片可用于 NewSendMessagesParam 与 NewInteractiveV1CardUpdateParam
+func (t *CardTemplates) Render(name, lang string, data interface{}) (*Card, error) {
+	b, err := t.RenderJSON(name, lang, data)
+	if err != nil {
+		return nil, err
+	}
+	var card Card
+	if err = json.Unmarshal(b, &card); err != nil {
+		return nil, fmt.Errorf("feishu: card template %s renders invalid json: %w", name, err)
+	}
+	if err = card.Validate(); err != nil {
+		return nil, fmt.Errorf("feishu: card template %s: %w", name, err)
+	}
+	return &card, nil
+}
+
+// RenderJSON 渲染卡片 JSON，不做校验
+func (t *CardTemplates) RenderJSON(name, lang string, data interface{}) ([]byte, error) {
+	tmpl := t.lookup(name, lang)
+	if tmpl == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCardTemplateNotFound, name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (t *CardTemplates) lookup(name, lang string) *template.Template {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	langs := t.templates[name]
+	if tmpl, ok := langs[lang]; ok {
+		return tmpl
+	}
+	if tmpl, ok := langs[t.DefaultLang]; ok {
+		return tmpl
+	}
+	// 按语言排序保证结果稳定
+	var fallback string
+	for l := range langs {
+		if fallback == "" || l < fallback {
+			fallback = l
+		}
+	}
+	return langs[fallback]
+}
+
+func (t *CardTemplates) compile(name, text string) (*template.Template, error) {
+	tmpl := template.New(name).Funcs(CardTemplateFuncs).Option("missingkey=error")
+	for partialName, partial := range t.partials {
+		if _, err := tmpl.New(partialName).Parse(partial); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl.Parse(text)
+}
+
+// compileAll 片段变化后重新编译所有模板，失败时保留原模板
+func (t *CardTemplates) compileAll() error {
+	templates := make(map[string]map[string]*template.Template, len(t.texts))
+	for name, langs := range t.texts {
+		templates[name] = make(map[string]*template.Template, len(langs))
+		for lang, text := range langs {
+			tmpl, err := t.compile(name, text)
+			if err != nil {
+				return err
+			}
+			templates[name][lang] = tmpl
+		}
+	}
+	t.templates = templates
+	return nil
+}
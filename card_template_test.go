@@ -0,0 +1,124 @@
+package feishu
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCardTemplates(t *testing.T) {
+	templates := NewCardTemplates()
+	if err := templates.Partial("footer", `{"tag":"note","elements":[{"tag":"plain_text","content":{{json .Service}}}]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := templates.Register("alert", LangZhCn, `{
+		"header": {"title": {"tag": "plain_text", "content": "告警"}, "template": "red"},
+		"elements": [
+			{"tag": "markdown", "content": {{.Reason | md | json}}},
+			{"tag": "action", "actions": [{{range $i, $b := .Buttons}}{{comma $i}}{"tag": "button", "text": {"tag": "plain_text", "content": {{json $b}}}, "value": {"action": {{json $b}}}}{{end}}]},
+			{{template "footer" .}}
+		]
+	}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := templates.Register("alert", LangEnUs, `{"header": {"title": {"tag": "plain_text", "content": "Alert"}}, "elements": [{{template "footer" .}}]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := templates.Register("broken", LangZhCn, `{"elements": [{"tag": "button", "text": {"tag": "plain_text", "content": "x"}}]}`); err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]interface{}{"Reason": "CPU *90%* <at>", "Service": "api", "Buttons": []string{"ack", "mute"}}
+	tests := []struct {
+		name string
+		lang string
+		want string
+	}{
+		{
+			name: "alert",
+			lang: LangZhCn,
+			want: `{"header":{"title":{"tag":"plain_text","content":"告警"},"template":"red"},"elements":[{"tag":"markdown","content":"CPU &#42;90%&#42; &lt;at&gt;"},{"tag":"action","actions":[{"tag":"button","text":{"tag":"plain_text","content":"ack"},"value":{"action":"ack"}},{"tag":"button","text":{"tag":"plain_text","content":"mute"},"value":{"action":"mute"}}]},{"tag":"note","elements":[{"tag":"plain_text","content":"api"}]}]}`,
+		},
+		{
+			name: "alert",
+			lang: LangEnUs,
+			want: `{"header":{"title":{"tag":"plain_text","content":"Alert"}},"elements":[{"tag":"note","elements":[{"tag":"plain_text","content":"api"}]}]}`,
+		},
+		{
+			name: "alert",
+			lang: LangJaJp,
+			want: `{"header":{"title":{"tag":"plain_text","content":"告警"},"template":"red"},"elements":[{"tag":"markdown","content":"CPU &#42;90%&#42; &lt;at&gt;"},{"tag":"action","actions":[{"tag":"button","text":{"tag":"plain_text","content":"ack"},"value":{"action":"ack"}},{"tag":"button","text":{"tag":"plain_text","content":"mute"},"value":{"action":"mute"}}]},{"tag":"note","elements":[{"tag":"plain_text","content":"api"}]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		card, err := templates.Render(tt.name, tt.lang, data)
+		if err != nil {
+			t.Fatalf("Render(%s, %s) error = %v", tt.name, tt.lang, err)
+		}
+		got, _ := json.Marshal(card)
+		if !jsonEqual(got, []byte(tt.want)) {
+			t.Errorf("Render(%s, %s) =\n%s\nwant\n%s", tt.name, tt.lang, got, tt.want)
+		}
+	}
+
+	var verr *CardValidationError
+	if _, err := templates.Render("broken", LangZhCn, nil); !errors.As(err, &verr) || verr.Path != "elements[0]" {
+		t.Errorf("Render(broken) error = %v", err)
+	}
+	if _, err := templates.Render("missing", LangZhCn, nil); !errors.Is(err, ErrCardTemplateNotFound) {
+		t.Errorf("Render(missing) error = %v", err)
+	}
+}
+
+// jsonEqual 比较 JSON 值，忽略 HTML 字符转义等格式差异
+func jsonEqual(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestCardUnmarshalJSON(t *testing.T) {
+	card, err := NewCardBuilder().
+		Header("标题", CardTemplateBlue).
+		Div(CardLarkMd("**a**"), CardField{IsShort: true, Text: CardPlainText("b")}).
+		Columns(&CardColumn{Width: "weighted", Weight: 1, Elements: []CardElement{&CardMarkdown{Content: "c"}}}).
+		Actions(NewCardButton("ok", CardButtonPrimary).WithValue(map[string]interface{}{"action": "ok"}), &CardDatePicker{TagName: CardTagPickerTime}).
+		Element(&CardDiv{Text: CardPlainText("d"), Extra: &CardImg{ImgKey: "img_1", Alt: CardPlainText("")}}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(card)
+
+	var decoded Card
+	if err = json.Unmarshal(want, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(&decoded)
+	if string(got) != string(want) {
+		t.Errorf("round trip =\n%s\nwant\n%s", got, want)
+	}
+
+	if err = json.Unmarshal([]byte(`{"elements":[{"tag":"chart","chart_spec":{}}]}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if raw, ok := decoded.Elements[0].(*CardRawElement); !ok || raw.Tag() != "chart" {
+		t.Errorf("unknown element = %#v", decoded.Elements[0])
+	}
+
+	i18n := []byte(`{"config":{"wide_screen_mode":true,"enable_forward":false},"i18n_header":{"zh_cn":{"title":{"tag":"plain_text","content":"标题"}}},"i18n_elements":{"zh_cn":[{"tag":"markdown","content":"a"}]},"elements":null}`)
+	var i18nCard Card
+	if err = json.Unmarshal(i18n, &i18nCard); err != nil {
+		t.Fatal(err)
+	}
+	if len(i18nCard.Extra) != 2 || i18nCard.Elements != nil {
+		t.Errorf("extra = %v, elements = %v", i18nCard.Extra, i18nCard.Elements)
+	}
+	got, _ = json.Marshal(&i18nCard)
+	if !jsonEqual(got, i18n) {
+		t.Errorf("i18n round trip =\n%s\nwant\n%s", got, i18n)
+	}
+}
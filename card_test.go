@@ -0,0 +1,88 @@
+package feishu
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCardBuilder(t *testing.T) {
+	card, err := NewCardBuilder().
+		Header("发布", CardTemplateGreen).
+		Markdown("**service-x** 已发布").
+		Div(nil, CardField{IsShort: true, Text: CardLarkMd("**环境**\nprod")}).
+		Hr().
+		Note(CardPlainText("by bot")).
+		Actions(
+			NewCardButton("回滚", CardButtonDanger).WithValue(map[string]interface{}{"action": "rollback"}).WithConfirm("确认", "确认回滚？"),
+			&CardDatePicker{TagName: CardTagPickerDatetime},
+		).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := json.Marshal(card)
+	want := `{"config":{"wide_screen_mode":true,"enable_forward":true},"header":{"title":{"tag":"plain_text","content":"发布"},"template":"green"},"elements":[` +
+		`{"tag":"markdown","content":"**service-x** 已发布"},` +
+		`{"tag":"div","fields":[{"is_short":true,"text":{"tag":"lark_md","content":"**环境**\nprod"}}]},` +
+		`{"tag":"hr"},` +
+		`{"tag":"note","elements":[{"tag":"plain_text","content":"by bot"}]},` +
+		`{"tag":"action","actions":[{"tag":"button","text":{"tag":"plain_text","content":"回滚"},"type":"danger","value":{"action":"rollback"},"confirm":{"title":{"tag":"plain_text","content":"确认"},"text":{"tag":"plain_text","content":"确认回滚？"}}},{"tag":"picker_datetime"}]}]}`
+	if string(b) != want {
+		t.Errorf("card = %s\nwant %s", b, want)
+	}
+}
+
+func TestCardValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		card     *Card
+		wantPath string
+	}{
+		{
+			name:     "button at top level",
+			card:     &Card{Elements: []CardElement{NewCardButton("x", "")}},
+			wantPath: "elements[0]",
+		},
+		{
+			name:     "select in note",
+			card:     &Card{Elements: []CardElement{&CardNote{Elements: []CardElement{&CardSelectStatic{}}}}},
+			wantPath: "elements[0].elements[0]",
+		},
+		{
+			name:     "column_set in column",
+			card:     &Card{Elements: []CardElement{&CardColumnSet{Columns: []*CardColumn{{Elements: []CardElement{&CardColumnSet{}}}}}}},
+			wantPath: "elements[0].columns[0].elements[0]",
+		},
+		{
+			name:     "empty overflow",
+			card:     &Card{Elements: []CardElement{&CardActionBlock{Actions: []CardElement{&CardOverflow{}}}}},
+			wantPath: "elements[0].actions[0]",
+		},
+		{
+			name:     "button in i18n elements",
+			card:     &Card{Extra: map[string]json.RawMessage{"i18n_elements": json.RawMessage(`{"zh_cn":[{"tag":"markdown","content":"a"},{"tag":"button","text":{"tag":"plain_text","content":"x"}}]}`)}},
+			wantPath: "i18n_elements.zh_cn[1]",
+		},
+		{
+			name:     "empty i18n header",
+			card:     &Card{Extra: map[string]json.RawMessage{"i18n_header": json.RawMessage(`{"en_us":{"title":{"tag":"plain_text","content":""}}}`)}},
+			wantPath: "i18n_header.en_us.title",
+		},
+		{
+			name:     "too large",
+			card:     &Card{Elements: []CardElement{&CardMarkdown{Content: strings.Repeat("a", CardMaxSize)}}},
+			wantPath: "card",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.card.Validate()
+			verr, ok := err.(*CardValidationError)
+			if !ok || verr.Path != tt.wantPath {
+				t.Errorf("Validate() error = %v, want path %s", err, tt.wantPath)
+			}
+		})
+	}
+}
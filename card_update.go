@@ -0,0 +1,203 @@
+package feishu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 卡片延迟更新 token 的限制
+const (
+	CardUpdateTokenTTL     = 30 * time.Minute // token 有效期
+	CardUpdateTokenMaxUses = 2                // token 最多可使用次数
+)
+
+var ErrCardNotUpdatable = errors.New("feishu: card can no longer be updated")
+
+// CardNotUpdatableCodes PatchMessages 返回这些错误码时视为卡片不能再更新，返回 ErrCardNotUpdatable
+var CardNotUpdatableCodes = map[int64]bool{
+	230011: true, // 消息已撤回
+	230054: true, // 消息类型不支持该操作，如非共享卡片
+	230110: true, // 消息已删除
+}
+
+// CardUpdateToken 卡片回调中的延迟更新 token
+type CardUpdateToken struct {
+	MessageId string
+	OpenId    string // 触发回调的用户
+	Token     string
+	ExpiresAt time.Time
+	Remaining int // 剩余可使用次数
+}
+
+// CardUpdateTracker 记录卡片回调中的延迟更新 token 及其有效期与剩余次数
+//
+// 同一卡片只保留最近一次回调的 token，新的回调会重新获得 30 分钟与 2 次的额度
+type CardUpdateTracker struct {
+	Now func() time.Time // 用于测试，默认 time.Now
+
+	mu     sync.Mutex
+	tokens map[string]*CardUpdateToken // message_id => token
+}
+
+// NewCardUpdateTracker 创建延迟更新 token 记录
+func NewCardUpdateTracker() *CardUpdateTracker {
+	return &CardUpdateTracker{tokens: make(map[string]*CardUpdateToken)}
+}
+
+func (t *CardUpdateTracker) now() time.Time {
+	if t.Now != nil {
+		return t.Now()
+	}
+	return time.Now()
+}
+
+// Track 记录卡片回调中的 token
+func (t *CardUpdateTracker) Track(action *CardAction) {
+	if action.Token == "" || action.OpenMessageId == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	for messageId, token := range t.tokens {
+		if !now.Before(token.ExpiresAt) {
+			delete(t.tokens, messageId)
+		}
+	}
+	t.tokens[action.OpenMessageId] = &CardUpdateToken{
+		MessageId: action.OpenMessageId,
+		OpenId:    action.OpenId,
+		Token:     action.Token,
+		ExpiresAt: now.Add(CardUpdateTokenTTL),
+		Remaining: CardUpdateTokenMaxUses,
+	}
+}
+
+// Get 获取卡片仍可使用的 token
+func (t *CardUpdateTracker) Get(messageId string) (CardUpdateToken, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	token, err := t.get(messageId)
+	if err != nil {
+		return CardUpdateToken{}, false
+	}
+	return *token, true
+}
+
+// acquire 占用一次 token 的使用次数，调用接口失败时也计入次数
+func (t *CardUpdateTracker) acquire(messageId string) (CardUpdateToken, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	token, err := t.get(messageId)
+	if err != nil {
+		return CardUpdateToken{}, err
+	}
+	token.Remaining--
+	if token.Remaining == 0 {
+		delete(t.tokens, messageId)
+	}
+	return *token, nil
+}
+
+func (t *CardUpdateTracker) get(messageId string) (*CardUpdateToken, error) {
+	token, ok := t.tokens[messageId]
+	if !ok {
+		return nil, fmt.Errorf("%w: no callback token for message %s", ErrCardNotUpdatable, messageId)
+	}
+	if !t.now().Before(token.ExpiresAt) {
+		delete(t.tokens, messageId)
+		return nil, fmt.Errorf("%w: callback token for message %s expired at %s", ErrCardNotUpdatable, messageId, token.ExpiresAt.Format(time.RFC3339))
+	}
+	return token, nil
+}
+
+// Wrap 记录卡片回调中的 token 后交给 next 处理
+func (t *CardUpdateTracker) Wrap(next CardActionHandlerFunc) CardActionHandlerFunc {
+	return func(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+		t.Track(action)
+		if next == nil {
+			return nil, nil
+		}
+		return next(ctx, action)
+	}
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// CardUpdater 按卡片类型选择更新方式
+//
+// 共享卡片（config.update_multi 为 true）通过 PatchMessages 按消息 ID 更新，发送 14 天内有效；
+// 其他卡片使用回调 token 通过 InteractiveV1CardUpdate 延迟更新，token 无效时返回 ErrCardNotUpdatable
+//
+// 更新方式由传入的新卡片的 update_multi 决定，而不是原消息，二者需保持一致：
+// 原消息为共享卡片时新卡片也需开启 update_multi，反之亦然
+//
+// 延迟更新需在回调响应之后调用，否则可能被回调的响应覆盖
+type CardUpdater struct {
+	Client  *Client
+	Tracker *CardUpdateTracker
+
+	mu      sync.Mutex
+	expired map[string]bool // PatchMessages 返回不能更新的消息
+}
+
+// NewCardUpdater 创建卡片更新器
+func NewCardUpdater(client *Client) *CardUpdater {
+	return &CardUpdater{Client: client, Tracker: NewCardUpdateTracker()}
+}
+
+// Update 更新卡片，openIds 仅对延迟更新生效，为空时更新所有人的卡片
+func (u *CardUpdater) Update(messageId string, card *Card, openIds ...string) error {
+	if card.Config != nil && card.Config.UpdateMulti {
+		param := PatchMessagesParam{MessageId: messageId}
+		if err := param.SetCard(card); err != nil {
+			return err
+		}
+		res, err := u.Client.PatchMessages(param)
+		if err != nil {
+			return err
+		}
+		if CardNotUpdatableCodes[res.Code] {
+			u.mu.Lock()
+			if u.expired == nil {
+				u.expired = make(map[string]bool)
+			}
+			u.expired[messageId] = true
+			u.mu.Unlock()
+			return fmt.Errorf("%w: patch card %s code %d msg %s", ErrCardNotUpdatable, messageId, res.Code, res.Msg)
+		}
+		if res.Code != 0 {
+			return fmt.Errorf("patch card %s code %d msg %s", messageId, res.Code, res.Msg)
+		}
+		return nil
+	}
+
+	token, err := u.Tracker.acquire(messageId)
+	if err != nil {
+		return err
+	}
+	res, err := u.Client.InteractiveV1CardUpdate(NewInteractiveV1CardUpdateParam(token.Token, card, openIds...))
+	if err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("update card %s code %d msg %s", messageId, res.Code, res.Msg)
+	}
+	return nil
+}
+
+// Updatable 判断卡片是否还能更新，与 Update 一样按 card 的 update_multi 选择更新方式
+//
+// 共享卡片在 PatchMessages 返回不能更新之前均视为可以更新
+func (u *CardUpdater) Updatable(messageId string, card *Card) bool {
+	if card.Config != nil && card.Config.UpdateMulti {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		return !u.expired[messageId]
+	}
+	_, ok := u.Tracker.Get(messageId)
+	return ok
+}
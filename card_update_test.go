@@ -0,0 +1,111 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCardUpdater(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/open-apis/interactive/v1/card/update" {
+			var param struct {
+				Token string `json:"token"`
+			}
+			if err := json.Unmarshal(body, &param); err != nil || param.Token != "c-token" {
+				t.Errorf("card update body = %s", body)
+			}
+		}
+		if r.URL.Path == "/open-apis/im/v1/messages/om_4" {
+			_, _ = w.Write([]byte(`{"code":230110,"msg":"message deleted"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"ok"}`))
+	}))
+
+	now := time.Now()
+	updater := NewCardUpdater(client)
+	updater.Tracker.Now = func() time.Time { return now }
+
+	handler := updater.Tracker.Wrap(func(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+		return nil, nil
+	})
+	_, _ = handler(context.Background(), &CardAction{OpenId: "ou_1", OpenMessageId: "om_1", Token: "c-token"})
+
+	card, _ := NewCardBuilder().Markdown("done").Build()
+	shared, _ := NewCardBuilder().Config(CardConfig{UpdateMulti: true}).Markdown("done").Build()
+
+	if err := updater.Update("om_1", card); err != nil {
+		t.Fatal(err)
+	}
+	if token, ok := updater.Tracker.Get("om_1"); !ok || token.Remaining != 1 {
+		t.Errorf("Get(om_1) = %+v, %v", token, ok)
+	}
+	if err := updater.Update("om_1", card, "ou_1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := updater.Update("om_1", card); !errors.Is(err, ErrCardNotUpdatable) {
+		t.Errorf("third update error = %v", err)
+	}
+	if updater.Updatable("om_1", card) || !updater.Updatable("om_1", shared) {
+		t.Error("Updatable(om_1) mismatch")
+	}
+
+	_, _ = handler(context.Background(), &CardAction{OpenMessageId: "om_2", Token: "c-token"})
+	now = now.Add(CardUpdateTokenTTL)
+	if err := updater.Update("om_2", card); !errors.Is(err, ErrCardNotUpdatable) {
+		t.Errorf("expired update error = %v", err)
+	}
+
+	if err := updater.Update("om_3", shared); err != nil {
+		t.Fatal(err)
+	}
+	if err := updater.Update("om_4", shared); !errors.Is(err, ErrCardNotUpdatable) {
+		t.Errorf("deleted update error = %v", err)
+	}
+	if !updater.Updatable("om_3", shared) || updater.Updatable("om_4", shared) {
+		t.Error("Updatable(shared) mismatch")
+	}
+
+	want := []string{
+		"POST /open-apis/interactive/v1/card/update",
+		"POST /open-apis/interactive/v1/card/update",
+		"PATCH /open-apis/im/v1/messages/om_3",
+		"PATCH /open-apis/im/v1/messages/om_4",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("requests = %v", requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("requests[%d] = %s, want %s", i, requests[i], want[i])
+		}
+	}
+}
+
+func TestNewInteractiveV1CardUpdateParam(t *testing.T) {
+	i18n := `{"card_link":{"url":"https://example.com"},"i18n_header":{"zh_cn":{"title":{"tag":"plain_text","content":"标题"}}},"i18n_elements":{"zh_cn":[{"tag":"markdown","content":"a"}]},"elements":null}`
+	var card Card
+	if err := json.Unmarshal([]byte(i18n), &card); err != nil {
+		t.Fatal(err)
+	}
+	if err := card.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(NewInteractiveV1CardUpdateParam("c-1", &card, "ou_1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"token":"c-1","card":{"open_ids":["ou_1"],"card_link":{"url":"https://example.com"},"i18n_header":{"zh_cn":{"title":{"tag":"plain_text","content":"标题"}}},"i18n_elements":{"zh_cn":[{"tag":"markdown","content":"a"}]},"elements":null}}`
+	if !jsonEqual(got, []byte(want)) {
+		t.Errorf("param =\n%s\nwant\n%s", got, want)
+	}
+}
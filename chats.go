@@ -0,0 +1,548 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// 群成员 ID 类型，用户与 SendMessagesParam.ReceiveIdType 一致，机器人为 app_id
+const (
+	MemberIdTypeOpenId  = ReceiveIdTypeOpenId
+	MemberIdTypeUserId  = ReceiveIdTypeUserId
+	MemberIdTypeUnionId = ReceiveIdTypeUnionId
+	MemberIdTypeAppId   = "app_id"
+)
+
+// ChatI18nNames 群名称国际化
+type ChatI18nNames struct {
+	ZhCn string `json:"zh_cn,omitempty"`
+	EnUs string `json:"en_us,omitempty"`
+	JaJp string `json:"ja_jp,omitempty"`
+}
+
+// ChatInfo 群信息，用于创建与更新群
+type ChatInfo struct {
+	Avatar                 string         `json:"avatar,omitempty"`
+	Name                   string         `json:"name,omitempty"`
+	Description            string         `json:"description,omitempty"`
+	I18nNames              *ChatI18nNames `json:"i18n_names,omitempty"`
+	OwnerId                string         `json:"owner_id,omitempty"`
+	ChatMode               string         `json:"chat_mode,omitempty"` // group
+	ChatType               string         `json:"chat_type,omitempty"` // private 或 public
+	External               bool           `json:"external,omitempty"`
+	JoinMessageVisibility  string         `json:"join_message_visibility,omitempty"`
+	LeaveMessageVisibility string         `json:"leave_message_visibility,omitempty"`
+	MembershipApproval     string         `json:"membership_approval,omitempty"`
+	AddMemberPermission    string         `json:"add_member_permission,omitempty"`
+	ShareCardPermission    string         `json:"share_card_permission,omitempty"`
+	AtAllPermission        string         `json:"at_all_permission,omitempty"`
+	EditPermission         string         `json:"edit_permission,omitempty"`
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// CreateChatsParam 创建群的请求结构体
+type CreateChatsParam struct {
+	UserIdType    string `json:"-"`
+	SetBotManager bool   `json:"-"`
+	ChatInfo
+	UserIdList []string `json:"user_id_list,omitempty"`
+	BotIdList  []string `json:"bot_id_list,omitempty"`
+}
+
+// CreateChatsRes 创建群的响应结构体
+type CreateChatsRes struct {
+	ResponseCode
+	Data CreateChatsResData `json:"data"`
+}
+
+type CreateChatsResData struct {
+	ChatId string `json:"chat_id"`
+	ChatInfo
+	TenantKey string `json:"tenant_key"`
+}
+
+// CreateChats 创建群
+func (c *Client) CreateChats(param CreateChatsParam) (*CreateChatsRes, error) {
+	params := url.Values{}
+	if param.UserIdType != "" {
+		params.Add("user_id_type", param.UserIdType)
+	}
+	if param.SetBotManager {
+		params.Add("set_bot_manager", "true")
+	}
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/chats?"+params.Encode(), strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data CreateChatsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// UpdateChatsParam 更新群信息的请求结构体
+type UpdateChatsParam struct {
+	ChatId     string `json:"-"`
+	UserIdType string `json:"-"`
+	ChatInfo
+}
+
+// UpdateChatsRes 更新群信息的响应结构体
+type UpdateChatsRes struct {
+	ResponseCode
+}
+
+// UpdateChats 更新群信息
+func (c *Client) UpdateChats(param UpdateChatsParam) (*UpdateChatsRes, error) {
+	params := url.Values{}
+	if param.UserIdType != "" {
+		params.Add("user_id_type", param.UserIdType)
+	}
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPut, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"?"+params.Encode(), strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data UpdateChatsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// DeleteChatsParam 解散群的请求结构体
+type DeleteChatsParam struct {
+	ChatId string
+}
+
+// DeleteChatsRes 解散群的响应结构体
+type DeleteChatsRes struct {
+	ResponseCode
+}
+
+// DeleteChats 解散群
+func (c *Client) DeleteChats(param DeleteChatsParam) (*DeleteChatsRes, error) {
+	request, _ := http.NewRequest(http.MethodDelete, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId, nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data DeleteChatsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// GetChatsParam 获取群信息的请求结构体
+type GetChatsParam struct {
+	ChatId     string
+	UserIdType string
+}
+
+// GetChatsRes 获取群信息的响应结构体
+type GetChatsRes struct {
+	ResponseCode
+	Data GetChatsResData `json:"data"`
+}
+
+type GetChatsResData struct {
+	ChatInfo
+	ChatTag              string `json:"chat_tag"`
+	OwnerIdType          string `json:"owner_id_type"`
+	ModerationPermission string `json:"moderation_permission"`
+	TenantKey            string `json:"tenant_key"`
+	UserCount            string `json:"user_count"`
+	BotCount             string `json:"bot_count"`
+}
+
+// GetChats 获取群信息
+func (c *Client) GetChats(param GetChatsParam) (*GetChatsRes, error) {
+	params := url.Values{}
+	if param.UserIdType != "" {
+		params.Add("user_id_type", param.UserIdType)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data GetChatsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ListChatsParam 获取机器人所在群列表的请求结构体
+type ListChatsParam struct {
+	UserIdType string `json:"user_id_type"`
+	PageSize   int64  `json:"page_size"`
+	PageToken  string `json:"page_token"`
+}
+
+// ListChatsRes 获取机器人所在群列表的响应结构体
+type ListChatsRes struct {
+	ResponseCode
+	Data ListChatsResData `json:"data"`
+}
+
+type ListChatsResData struct {
+	HasMore   bool                   `json:"has_more"`
+	PageToken string                 `json:"page_token"`
+	Items     []ListChatsResDataItem `json:"items"`
+}
+
+type ListChatsResDataItem struct {
+	ChatId      string `json:"chat_id"`
+	Avatar      string `json:"avatar"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	OwnerId     string `json:"owner_id"`
+	OwnerIdType string `json:"owner_id_type"`
+	External    bool   `json:"external"`
+	TenantKey   string `json:"tenant_key"`
+}
+
+// ListChats 获取机器人所在群列表
+func (c *Client) ListChats(param ListChatsParam) (*ListChatsRes, error) {
+	params := url.Values{}
+	if param.UserIdType != "" {
+		params.Add("user_id_type", param.UserIdType)
+	}
+	if param.PageSize > 0 {
+		params.Add("page_size", fmt.Sprintf("%v", param.PageSize))
+	}
+	if param.PageToken != "" {
+		params.Add("page_token", param.PageToken)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/chats?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ListChatsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ChatMembersParam 将用户或机器人拉入群、移出群的请求结构体
+type ChatMembersParam struct {
+	ChatId       string   `json:"-"`
+	MemberIdType string   `json:"-"` // 用户 ID 类型，机器人为 app_id
+	IdList       []string `json:"id_list"`
+}
+
+// ChatMembersRes 将用户或机器人拉入群、移出群的响应结构体
+type ChatMembersRes struct {
+	ResponseCode
+	Data ChatMembersResData `json:"data"`
+}
+
+type ChatMembersResData struct {
+	InvalidIdList         []string `json:"invalid_id_list"`
+	NotExistedIdList      []string `json:"not_existed_id_list"`
+	PendingApprovalIdList []string `json:"pending_approval_id_list"`
+}
+
+// AddChatMembers 将用户或机器人拉入群
+func (c *Client) AddChatMembers(param ChatMembersParam) (*ChatMembersRes, error) {
+	return c.chatMembers(http.MethodPost, param)
+}
+
+// RemoveChatMembers 将用户或机器人移出群
+func (c *Client) RemoveChatMembers(param ChatMembersParam) (*ChatMembersRes, error) {
+	return c.chatMembers(http.MethodDelete, param)
+}
+
+func (c *Client) chatMembers(method string, param ChatMembersParam) (*ChatMembersRes, error) {
+	params := url.Values{}
+	if param.MemberIdType != "" {
+		params.Add("member_id_type", param.MemberIdType)
+	}
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(method, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"/members?"+params.Encode(), strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ChatMembersRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ListChatMembersParam 获取群成员列表的请求结构体，不包含机器人
+type ListChatMembersParam struct {
+	ChatId       string `json:"-"`
+	MemberIdType string `json:"member_id_type"`
+	PageSize     int64  `json:"page_size"`
+	PageToken    string `json:"page_token"`
+}
+
+// ListChatMembersRes 获取群成员列表的响应结构体
+type ListChatMembersRes struct {
+	ResponseCode
+	Data ListChatMembersResData `json:"data"`
+}
+
+type ListChatMembersResData struct {
+	HasMore     bool                         `json:"has_more"`
+	PageToken   string                       `json:"page_token"`
+	MemberTotal int64                        `json:"member_total"`
+	Items       []ListChatMembersResDataItem `json:"items"`
+}
+
+type ListChatMembersResDataItem struct {
+	MemberIdType string `json:"member_id_type"`
+	MemberId     string `json:"member_id"`
+	Name         string `json:"name"`
+	TenantKey    string `json:"tenant_key"`
+}
+
+// ListChatMembers 获取群成员列表
+func (c *Client) ListChatMembers(param ListChatMembersParam) (*ListChatMembersRes, error) {
+	params := url.Values{}
+	if param.MemberIdType != "" {
+		params.Add("member_id_type", param.MemberIdType)
+	}
+	if param.PageSize > 0 {
+		params.Add("page_size", fmt.Sprintf("%v", param.PageSize))
+	}
+	if param.PageToken != "" {
+		params.Add("page_token", param.PageToken)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"/members?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ListChatMembersRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// IsInChatParam 判断用户或机器人是否在群里的请求结构体
+type IsInChatParam struct {
+	ChatId string
+}
+
+// IsInChatRes 判断用户或机器人是否在群里的响应结构体
+type IsInChatRes struct {
+	ResponseCode
+	Data struct {
+		IsInChat bool `json:"is_in_chat"`
+	} `json:"data"`
+}
+
+// IsInChat 判断当前身份（机器人或用户）是否在群里
+func (c *Client) IsInChat(param IsInChatParam) (*IsInChatRes, error) {
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"/members/is_in_chat", nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data IsInChatRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ChatManagersParam 指定、删除群管理员的请求结构体
+type ChatManagersParam struct {
+	ChatId       string   `json:"-"`
+	MemberIdType string   `json:"-"`
+	ManagerIds   []string `json:"manager_ids"`
+}
+
+// ChatManagersRes 指定、删除群管理员的响应结构体
+type ChatManagersRes struct {
+	ResponseCode
+	Data struct {
+		ChatManagers    []string `json:"chat_managers"`
+		ChatBotManagers []string `json:"chat_bot_managers"`
+	} `json:"data"`
+}
+
+// AddChatManagers 指定群管理员
+func (c *Client) AddChatManagers(param ChatManagersParam) (*ChatManagersRes, error) {
+	return c.chatManagers("add_managers", param)
+}
+
+// DeleteChatManagers 删除群管理员
+func (c *Client) DeleteChatManagers(param ChatManagersParam) (*ChatManagersRes, error) {
+	return c.chatManagers("delete_managers", param)
+}
+
+func (c *Client) chatManagers(action string, param ChatManagersParam) (*ChatManagersRes, error) {
+	params := url.Values{}
+	if param.MemberIdType != "" {
+		params.Add("member_id_type", param.MemberIdType)
+	}
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"/managers/"+action+"?"+params.Encode(), strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ChatManagersRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// GetChatAnnouncementParam 获取群公告的请求结构体
+type GetChatAnnouncementParam struct {
+	ChatId     string
+	UserIdType string
+}
+
+// GetChatAnnouncementRes 获取群公告的响应结构体
+type GetChatAnnouncementRes struct {
+	ResponseCode
+	Data GetChatAnnouncementResData `json:"data"`
+}
+
+type GetChatAnnouncementResData struct {
+	Content        string `json:"content"` // 云文档序列化后的内容
+	Revision       string `json:"revision"`
+	CreateTime     string `json:"create_time"`
+	UpdateTime     string `json:"update_time"`
+	OwnerIdType    string `json:"owner_id_type"`
+	OwnerId        string `json:"owner_id"`
+	ModifierIdType string `json:"modifier_id_type"`
+	ModifierId     string `json:"modifier_id"`
+}
+
+// GetChatAnnouncement 获取群公告
+func (c *Client) GetChatAnnouncement(param GetChatAnnouncementParam) (*GetChatAnnouncementRes, error) {
+	params := url.Values{}
+	if param.UserIdType != "" {
+		params.Add("user_id_type", param.UserIdType)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"/announcement?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data GetChatAnnouncementRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// UpdateChatAnnouncementParam 更新群公告的请求结构体
+type UpdateChatAnnouncementParam struct {
+	ChatId   string   `json:"-"`
+	Revision string   `json:"revision"` // 当前公告版本号，取自 GetChatAnnouncement
+	Requests []string `json:"requests"` // 云文档修改操作的序列化字符串
+}
+
+// UpdateChatAnnouncementRes 更新群公告的响应结构体
+type UpdateChatAnnouncementRes struct {
+	ResponseCode
+}
+
+// UpdateChatAnnouncement 更新群公告
+func (c *Client) UpdateChatAnnouncement(param UpdateChatAnnouncementParam) (*UpdateChatAnnouncementRes, error) {
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPatch, ServerUrl+"/open-apis/im/v1/chats/"+param.ChatId+"/announcement", strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data UpdateChatAnnouncementRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
@@ -0,0 +1,153 @@
+package feishu
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// apiRequest 测试服务收到的请求
+type apiRequest struct {
+	Method string
+	Url    string // 路径与查询参数
+	Body   string
+}
+
+// newRecordingClient 创建记录请求的测试客户端，所有请求均返回 response
+func newRecordingClient(t *testing.T, response string) (*Client, *[]apiRequest) {
+	var requests []apiRequest
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, apiRequest{Method: r.Method, Url: r.URL.RequestURI(), Body: string(body)})
+		_, _ = w.Write([]byte(response))
+	}))
+	return client, &requests
+}
+
+// checkRequest 校验最后一次请求的方法、地址与请求体，请求体按 JSON 比较
+func checkRequest(t *testing.T, requests []apiRequest, want apiRequest) {
+	t.Helper()
+	if len(requests) == 0 {
+		t.Fatal("no request")
+	}
+	got := requests[len(requests)-1]
+	if got.Method != want.Method || got.Url != want.Url {
+		t.Errorf("request = %s %s, want %s %s", got.Method, got.Url, want.Method, want.Url)
+	}
+	if want.Body == "" {
+		if got.Body != "" {
+			t.Errorf("body = %s, want empty", got.Body)
+		}
+	} else if !jsonEqual([]byte(got.Body), []byte(want.Body)) {
+		t.Errorf("body = %s, want %s", got.Body, want.Body)
+	}
+}
+
+func TestChatMembers(t *testing.T) {
+	client, requests := newRecordingClient(t, `{"code":0,"data":{"invalid_id_list":["ou_x"],"chat_managers":["ou_1"]}}`)
+
+	tests := []struct {
+		name string
+		call func() error
+		want apiRequest
+	}{
+		{
+			name: "add members",
+			call: func() error {
+				res, err := client.AddChatMembers(ChatMembersParam{ChatId: "oc_1", MemberIdType: MemberIdTypeOpenId, IdList: []string{"ou_1", "ou_x"}})
+				if err == nil && (len(res.Data.InvalidIdList) != 1 || res.Data.InvalidIdList[0] != "ou_x") {
+					t.Errorf("AddChatMembers() = %+v", res)
+				}
+				return err
+			},
+			want: apiRequest{http.MethodPost, "/open-apis/im/v1/chats/oc_1/members?member_id_type=open_id", `{"id_list":["ou_1","ou_x"]}`},
+		},
+		{
+			name: "remove bots",
+			call: func() error {
+				_, err := client.RemoveChatMembers(ChatMembersParam{ChatId: "oc_1", MemberIdType: MemberIdTypeAppId, IdList: []string{"cli_1"}})
+				return err
+			},
+			want: apiRequest{http.MethodDelete, "/open-apis/im/v1/chats/oc_1/members?member_id_type=app_id", `{"id_list":["cli_1"]}`},
+		},
+		{
+			name: "add managers",
+			call: func() error {
+				res, err := client.AddChatManagers(ChatManagersParam{ChatId: "oc_1", MemberIdType: MemberIdTypeOpenId, ManagerIds: []string{"ou_1"}})
+				if err == nil && (len(res.Data.ChatManagers) != 1 || res.Data.ChatManagers[0] != "ou_1") {
+					t.Errorf("AddChatManagers() = %+v", res)
+				}
+				return err
+			},
+			want: apiRequest{http.MethodPost, "/open-apis/im/v1/chats/oc_1/managers/add_managers?member_id_type=open_id", `{"manager_ids":["ou_1"]}`},
+		},
+		{
+			name: "delete managers",
+			call: func() error {
+				_, err := client.DeleteChatManagers(ChatManagersParam{ChatId: "oc_1", ManagerIds: []string{"ou_1"}})
+				return err
+			},
+			want: apiRequest{http.MethodPost, "/open-apis/im/v1/chats/oc_1/managers/delete_managers?", `{"manager_ids":["ou_1"]}`},
+		},
+		{
+			name: "list members",
+			call: func() error {
+				_, err := client.ListChatMembers(ListChatMembersParam{ChatId: "oc_1", PageSize: 20, PageToken: "p2"})
+				return err
+			},
+			want: apiRequest{http.MethodGet, "/open-apis/im/v1/chats/oc_1/members?page_size=20&page_token=p2", ""},
+		},
+		{
+			name: "is in chat",
+			call: func() error {
+				_, err := client.IsInChat(IsInChatParam{ChatId: "oc_1"})
+				return err
+			},
+			want: apiRequest{http.MethodGet, "/open-apis/im/v1/chats/oc_1/members/is_in_chat", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatal(err)
+			}
+			checkRequest(t, *requests, tt.want)
+		})
+	}
+}
+
+func TestChats(t *testing.T) {
+	client, requests := newRecordingClient(t, `{"code":0,"data":{"chat_id":"oc_1","revision":"3"}}`)
+
+	res, err := client.CreateChats(CreateChatsParam{
+		UserIdType:    ReceiveIdTypeOpenId,
+		SetBotManager: true,
+		ChatInfo:      ChatInfo{Name: "告警", ChatType: "private"},
+		UserIdList:    []string{"ou_1"},
+	})
+	if err != nil || res.Data.ChatId != "oc_1" {
+		t.Fatalf("CreateChats() = %+v, %v", res, err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPost, "/open-apis/im/v1/chats?set_bot_manager=true&user_id_type=open_id", `{"name":"告警","chat_type":"private","user_id_list":["ou_1"]}`})
+
+	if _, err = client.UpdateChats(UpdateChatsParam{ChatId: "oc_1", ChatInfo: ChatInfo{Description: "d"}}); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPut, "/open-apis/im/v1/chats/oc_1?", `{"description":"d"}`})
+
+	announcement, err := client.GetChatAnnouncement(GetChatAnnouncementParam{ChatId: "oc_1", UserIdType: ReceiveIdTypeOpenId})
+	if err != nil || announcement.Data.Revision != "3" {
+		t.Fatalf("GetChatAnnouncement() = %+v, %v", announcement, err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodGet, "/open-apis/im/v1/chats/oc_1/announcement?user_id_type=open_id", ""})
+
+	_, err = client.UpdateChatAnnouncement(UpdateChatAnnouncementParam{
+		ChatId:   "oc_1",
+		Revision: announcement.Data.Revision,
+		Requests: []string{`{"requestType":"InsertBlocksRequestType"}`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPatch, "/open-apis/im/v1/chats/oc_1/announcement", `{"revision":"3","requests":["{\"requestType\":\"InsertBlocksRequestType\"}"]}`})
+}
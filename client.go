@@ -67,22 +67,7 @@ type Client struct {
 // Do 执行 请求
 func (client *Client) Do(req *http.Request, accessToken string) (resp []byte, err error) {
 
-	// 默认 Header Content-Type
-	if req.Header.Get("Content-Type") == "" {
-		req.Header.Set("Content-Type", contentTypeApplicationJson)
-	}
-
-	// 添加 access_token
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-
-	// 添加 User-Agent
-	req.Header.Set("User-Agent", UserAgent)
-
-	if Logger != nil {
-		Logger.Printf("%s %s %v", req.Method, req.URL.String(), req.Header)
-	}
-
-	response, err := client.HttpClient.Do(req)
+	response, err := client.do(req, accessToken)
 	if err != nil {
 		return
 	}
@@ -100,3 +85,24 @@ func (client *Client) Do(req *http.Request, accessToken string) (resp []byte, er
 
 	return
 }
+
+// do 执行 请求，返回未读取的响应，由调用方关闭 Body
+func (client *Client) do(req *http.Request, accessToken string) (response *http.Response, err error) {
+
+	// 默认 Header Content-Type
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentTypeApplicationJson)
+	}
+
+	// 添加 access_token
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	// 添加 User-Agent
+	req.Header.Set("User-Agent", UserAgent)
+
+	if Logger != nil {
+		Logger.Printf("%s %s %v", req.Method, req.URL.String(), req.Header)
+	}
+
+	return client.HttpClient.Do(req)
+}
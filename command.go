@@ -0,0 +1,664 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	ErrCommandForbidden     = errors.New("feishu: command forbidden")
+	ErrCommandUnclosedQuote = errors.New("feishu: command has unclosed quote")
+)
+
+// CommandHandler 命令处理函数
+type CommandHandler func(ctx *CommandContext) error
+
+// CommandMiddleware 命令中间件，用于鉴权、日志等
+type CommandMiddleware func(next CommandHandler) CommandHandler
+
+// CommandFlag 命令选项，支持 --name value、--name=value 与布尔选项 --name
+type CommandFlag struct {
+	Name    string
+	Usage   string
+	Default string
+	Bool    bool
+}
+
+// Command 机器人命令
+type Command struct {
+	Name        string
+	Aliases     []string
+	Usage       string // 位置参数说明，如 <service>
+	Description string
+	Flags       []CommandFlag
+	Handler     CommandHandler // 为空时只作为子命令分组，执行时返回帮助
+	Middlewares []CommandMiddleware
+
+	parent      *Command
+	subcommands []*Command
+}
+
+// Command 注册子命令
+func (c *Command) Command(sub *Command) *Command {
+	sub.parent = c
+	c.subcommands = append(c.subcommands, sub)
+	return sub
+}
+
+// Path 命令的完整路径，如 deploy rollback
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.Path() + " " + c.Name
+}
+
+// Help 生成命令帮助
+func (c *Command) Help() string {
+	var b strings.Builder
+	b.WriteString("用法: " + c.Path())
+	if len(c.subcommands) > 0 {
+		b.WriteString(" <子命令>")
+	}
+	if c.Usage != "" {
+		b.WriteString(" " + c.Usage)
+	}
+	if len(c.Flags) > 0 {
+		b.WriteString(" [选项]")
+	}
+	if c.Description != "" {
+		b.WriteString("\n" + c.Description)
+	}
+	if len(c.subcommands) > 0 {
+		b.WriteString("\n子命令:")
+		writeCommandList(&b, c.subcommands)
+	}
+	if len(c.Flags) > 0 {
+		b.WriteString("\n选项:")
+		for _, f := range c.Flags {
+			b.WriteString("\n  --" + f.Name)
+			if !f.Bool {
+				b.WriteString(" <值>")
+			}
+			if f.Usage != "" {
+				b.WriteString("  " + f.Usage)
+			}
+			if f.Default != "" && !f.Bool {
+				b.WriteString("（默认 " + f.Default + "）")
+			}
+		}
+	}
+	return b.String()
+}
+
+func (c *Command) match(name string) bool {
+	if strings.EqualFold(c.Name, name) {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse 解析选项与位置参数，选项可以出现在位置参数之间，-- 之后均视为位置参数
+//
+// -5 等数字视为位置参数，紧跟在字符串选项后时作为选项值
+func (c *Command) parse(args []string) (positional []string, flags map[string]string, err error) {
+	fs := flag.NewFlagSet(c.Path(), flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	values := make(map[string]*string)
+	bools := make(map[string]*bool)
+	for _, f := range c.Flags {
+		if f.Bool {
+			bools[f.Name] = fs.Bool(f.Name, f.Default == "true", f.Usage)
+		} else {
+			values[f.Name] = fs.String(f.Name, f.Default, f.Usage)
+		}
+	}
+
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			args, rest = args[:i], args[i+1:]
+			break
+		}
+	}
+	for len(args) > 0 {
+		if isCommandNumber(args[0]) {
+			positional = append(positional, args[0])
+			args = args[1:]
+			continue
+		}
+		// 只把下一个作为位置参数的数字之前的部分交给 flag 解析
+		end := len(args)
+		for i := 1; i < len(args); i++ {
+			if isCommandNumber(args[i]) && !c.takesValue(args[i-1]) {
+				end = i
+				break
+			}
+		}
+		if err = fs.Parse(args[:end]); err != nil {
+			return nil, nil, err
+		}
+		remaining := append(append([]string(nil), fs.Args()...), args[end:]...)
+		if len(fs.Args()) > 0 {
+			positional = append(positional, remaining[0])
+			remaining = remaining[1:]
+		}
+		args = remaining
+	}
+	positional = append(positional, rest...)
+
+	flags = make(map[string]string, len(values)+len(bools))
+	for name, v := range values {
+		flags[name] = *v
+	}
+	for name, v := range bools {
+		flags[name] = strconv.FormatBool(*v)
+	}
+	return positional, flags, nil
+}
+
+// takesValue 判断参数是否为需要单独取值的字符串选项，如 --count
+func (c *Command) takesValue(arg string) bool {
+	if !strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+		return false
+	}
+	name := strings.TrimLeft(arg, "-")
+	for _, f := range c.Flags {
+		if f.Name == name {
+			return !f.Bool
+		}
+	}
+	return false
+}
+
+func isCommandNumber(arg string) bool {
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// CommandContext 命令执行上下文
+//
+// 由文本消息触发时 Message 不为空，由机器人菜单触发时 Menu 不为空
+type CommandContext struct {
+	context.Context
+	Client  *Client
+	Event   *Event
+	Message *MessageReceiveEvent
+	Menu    *BotMenuEvent
+	Command *Command
+	Args    []string          // 位置参数，@ 其他用户时为 @_user_N 占位符，可通过 Mention 获取用户
+	Flags   map[string]string // 选项值，未指定时为默认值
+}
+
+// SenderId 发送者，Message 与 Menu 均为空时返回零值
+func (c *CommandContext) SenderId() EventUserId {
+	if c.Menu != nil {
+		return c.Menu.Operator.OperatorId
+	}
+	if c.Message == nil {
+		return EventUserId{}
+	}
+	return c.Message.Sender.SenderId
+}
+
+// ChatId 会话 ID，菜单触发时为空
+func (c *CommandContext) ChatId() string {
+	if c.Message == nil {
+		return ""
+	}
+	return c.Message.Message.ChatId
+}
+
+// Flag 获取选项值
+func (c *CommandContext) Flag(name string) string {
+	return c.Flags[name]
+}
+
+// BoolFlag 获取布尔选项值
+func (c *CommandContext) BoolFlag(name string) bool {
+	v, _ := strconv.ParseBool(c.Flags[name])
+	return v
+}
+
+// Mention 获取参数中 @_user_N 占位符对应的用户
+func (c *CommandContext) Mention(arg string) (MessageReceiveEventMention, bool) {
+	if c.Message == nil {
+		return MessageReceiveEventMention{}, false
+	}
+	for _, m := range c.Message.Message.Mentions {
+		if m.Key == arg {
+			return m, true
+		}
+	}
+	return MessageReceiveEventMention{}, false
+}
+
+// Reply 回复触发命令的消息，菜单触发时单聊发送给操作者
+func (c *CommandContext) Reply(content MessageContent) error {
+	if c.Message == nil {
+		param, err := NewSendMessagesParam(ReceiveIdTypeOpenId, c.SenderId().OpenId, content)
+		if err != nil {
+			return err
+		}
+		res, err := c.Client.SendMessages(param)
+		if err != nil {
+			return err
+		}
+		if res.Code != 0 {
+			return fmt.Errorf("send messages code %d msg %s", res.Code, res.Msg)
+		}
+		return nil
+	}
+
+	// 话题中的命令在原话题内回复，避免刷屏主会话
+	param := ReplyMessagesParam{MessageId: c.Message.Message.MessageId, ReplyInThread: c.Message.Message.ThreadId != ""}
+	if err := param.SetContent(content); err != nil {
+		return err
+	}
+	res, err := c.Client.ReplyMessages(param)
+	if err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("reply messages code %d msg %s", res.Code, res.Msg)
+	}
+	return nil
+}
+
+// ReplyText 以文本回复触发命令的消息
+func (c *CommandContext) ReplyText(text string) error {
+	return c.Reply(NewTextContent(text))
+}
+
+// ReplyEphemeral 在群聊中发送仅触发命令的用户可见的卡片，单聊与菜单触发时直接回复卡片
+func (c *CommandContext) ReplyEphemeral(card *Card) error {
+	if c.Message == nil || c.Message.Message.ChatType == "p2p" {
+		return c.Reply(card)
+	}
+	res, err := c.Client.SendEphemeral(NewSendEphemeralParam(c.ChatId(), c.SenderId().OpenId, card))
+	if err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("send ephemeral code %d msg %s", res.Code, res.Msg)
+	}
+	return nil
+}
+
+// CommandRouter 命令路由，解析 “@机器人 deploy service-x --env prod” 形式的文本消息与机器人菜单事件
+type CommandRouter struct {
+	Client    *Client
+	BotOpenId string                               // 机器人 open_id，设置后群聊中只处理 @ 机器人的消息，可通过 LoadBotInfo 获取
+	OnError   func(ctx *CommandContext, err error) // 命令执行失败时调用，默认回复错误信息
+	NotFound  CommandHandler                       // 未匹配到命令时调用，默认回复帮助
+
+	commands    []*Command
+	middlewares []CommandMiddleware
+}
+
+// NewCommandRouter 创建命令路由
+func NewCommandRouter(client *Client) *CommandRouter {
+	return &CommandRouter{Client: client}
+}
+
+// Use 添加对所有命令生效的中间件
+func (r *CommandRouter) Use(middlewares ...CommandMiddleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Command 注册命令
+func (r *CommandRouter) Command(cmd *Command) *Command {
+	r.commands = append(r.commands, cmd)
+	return cmd
+}
+
+// Handle 注册没有选项的简单命令
+func (r *CommandRouter) Handle(name, description string, handler CommandHandler) *Command {
+	return r.Command(&Command{Name: name, Description: description, Handler: handler})
+}
+
+// Register 将命令路由注册到事件分发器的接收消息与机器人菜单事件
+func (r *CommandRouter) Register(d *EventDispatcher) {
+	d.OnMessageReceive(r.HandleMessage)
+	d.OnBotMenu(r.HandleBotMenu)
+}
+
+// LoadBotInfo 通过 GetBotInfo 获取机器人 open_id，之后群聊中只处理 @ 机器人的消息
+func (r *CommandRouter) LoadBotInfo() error {
+	res, err := r.Client.GetBotInfo()
+	if err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("get bot info code %d msg %s", res.Code, res.Msg)
+	}
+	r.BotOpenId = res.Bot.OpenId
+	return nil
+}
+
+// Help 生成命令列表
+func (r *CommandRouter) Help() string {
+	var b strings.Builder
+	b.WriteString("可用命令:")
+	writeCommandList(&b, r.commands)
+	b.WriteString("\n  help [命令]  查看帮助")
+	return b.String()
+}
+
+func writeCommandList(b *strings.Builder, commands []*Command) {
+	sorted := append([]*Command(nil), commands...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for _, cmd := range sorted {
+		b.WriteString("\n  " + cmd.Path())
+		if cmd.Usage != "" {
+			b.WriteString(" " + cmd.Usage)
+		}
+		if cmd.Description != "" {
+			b.WriteString("  " + cmd.Description)
+		}
+	}
+}
+
+// HandleMessage 处理接收消息事件，非文本消息与非命令消息会被忽略
+//
+// 命令执行失败不会返回 error，避免异步分发时重试导致命令重复执行
+func (r *CommandRouter) HandleMessage(ctx context.Context, event *Event, data *MessageReceiveEvent) error {
+	if data.Message.MessageType != MsgTypeText {
+		return nil
+	}
+	var body struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(data.Message.Content), &body); err != nil {
+		return err
+	}
+	text, ok := r.stripMention(body.Text, data)
+	if !ok {
+		return nil
+	}
+
+	c := &CommandContext{Context: ctx, Client: r.Client, Event: event, Message: data}
+	args, err := SplitCommandArgs(text)
+	if err != nil {
+		r.onError(c, err)
+		return nil
+	}
+	return r.run(c, args)
+}
+
+// HandleBotMenu 处理机器人自定义菜单事件，菜单的事件 key 按命令解析，如 “deploy” 或 “deploy rollback”
+//
+// 回复以单聊消息发送给点击菜单的用户
+func (r *CommandRouter) HandleBotMenu(ctx context.Context, event *Event, data *BotMenuEvent) error {
+	c := &CommandContext{Context: ctx, Client: r.Client, Event: event, Menu: data}
+	args, err := SplitCommandArgs(data.EventKey)
+	if err != nil {
+		r.onError(c, err)
+		return nil
+	}
+	return r.run(c, args)
+}
+
+// run 执行命令，args[0] 为命令名
+func (r *CommandRouter) run(c *CommandContext, args []string) error {
+	if len(args) == 0 {
+		return r.reply(c, r.Help())
+	}
+
+	name := strings.TrimPrefix(args[0], "/")
+	if strings.EqualFold(name, "help") && findCommand(r.commands, "help") == nil {
+		if cmd, _ := r.match(args[1:]); cmd != nil {
+			return r.reply(c, cmd.Help())
+		}
+		return r.reply(c, r.Help())
+	}
+
+	cmd, rest := r.match(args)
+	if cmd == nil {
+		c.Args = args
+		if r.NotFound != nil {
+			if err := r.NotFound(c); err != nil {
+				r.onError(c, err)
+			}
+			return nil
+		}
+		return r.reply(c, "未知命令 "+args[0]+"\n"+r.Help())
+	}
+	c.Command = cmd
+
+	var err error
+	c.Args, c.Flags, err = cmd.parse(rest)
+	if err == flag.ErrHelp {
+		return r.reply(c, cmd.Help())
+	}
+	if err != nil {
+		return r.reply(c, err.Error()+"\n"+cmd.Help())
+	}
+	if cmd.Handler == nil {
+		return r.reply(c, cmd.Help())
+	}
+
+	// 中间件由外到内依次为路由、父命令、子命令
+	handler := cmd.Handler
+	for p := cmd; p != nil; p = p.parent {
+		for i := len(p.Middlewares) - 1; i >= 0; i-- {
+			handler = p.Middlewares[i](handler)
+		}
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	if err := handler(c); err != nil {
+		r.onError(c, err)
+	}
+	return nil
+}
+
+// match 依次匹配命令与子命令，返回剩余参数
+func (r *CommandRouter) match(args []string) (*Command, []string) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	cmd := findCommand(r.commands, strings.TrimPrefix(args[0], "/"))
+	if cmd == nil {
+		return nil, nil
+	}
+	args = args[1:]
+	for len(args) > 0 {
+		sub := findCommand(cmd.subcommands, args[0])
+		if sub == nil {
+			break
+		}
+		cmd, args = sub, args[1:]
+	}
+	return cmd, args
+}
+
+func findCommand(commands []*Command, name string) *Command {
+	for _, cmd := range commands {
+		if cmd.match(name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// stripMention 去掉对机器人的 @，群聊中未 @ 机器人时返回 false
+func (r *CommandRouter) stripMention(text string, data *MessageReceiveEvent) (string, bool) {
+	text = strings.TrimSpace(text)
+	if r.BotOpenId == "" {
+		// 未配置机器人 open_id 时去掉开头的所有 @，群聊中没有以 @ 开头的消息不视为命令
+		mentioned := false
+		for {
+			found := false
+			for _, m := range data.Message.Mentions {
+				if strings.HasPrefix(text, m.Key) {
+					text, found = strings.TrimSpace(strings.TrimPrefix(text, m.Key)), true
+				}
+			}
+			if !found {
+				return text, mentioned || data.Message.ChatType == "p2p"
+			}
+			mentioned = true
+		}
+	}
+
+	mentioned := false
+	for _, m := range data.Message.Mentions {
+		if m.Id.OpenId == r.BotOpenId {
+			mentioned = true
+			text = strings.Replace(text, m.Key, "", -1)
+		}
+	}
+	if !mentioned && data.Message.ChatType != "p2p" {
+		return "", false
+	}
+	return strings.TrimSpace(text), true
+}
+
+func (r *CommandRouter) reply(c *CommandContext, text string) error {
+	if err := c.ReplyText(text); err != nil && Logger != nil {
+		Logger.Printf("command reply error %s", err)
+	}
+	return nil
+}
+
+func (r *CommandRouter) onError(c *CommandContext, err error) {
+	if r.OnError != nil {
+		r.OnError(c, err)
+		return
+	}
+	if errors.Is(err, ErrCommandForbidden) {
+		_ = r.reply(c, "没有权限执行该命令")
+		return
+	}
+	_ = r.reply(c, "命令执行失败: "+err.Error())
+}
+
+// SplitCommandArgs 按空白拆分命令参数，支持单引号、双引号、中文引号与反斜杠转义
+func SplitCommandArgs(s string) ([]string, error) {
+	var args []string
+	var b strings.Builder
+	var closer rune
+	inArg, escaped := false, false
+	for _, ch := range s {
+		switch {
+		case escaped:
+			b.WriteRune(ch)
+			escaped = false
+		case ch == '\\' && closer != '\'':
+			escaped, inArg = true, true
+		case closer != 0:
+			if ch == closer {
+				closer = 0
+			} else {
+				b.WriteRune(ch)
+			}
+		case ch == '"' || ch == '\'':
+			closer, inArg = ch, true
+		case ch == '“':
+			closer, inArg = '”', true
+		case ch == '‘':
+			closer, inArg = '’', true
+		case unicode.IsSpace(ch):
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		default:
+			b.WriteRune(ch)
+			inArg = true
+		}
+	}
+	if closer != 0 || escaped {
+		return nil, ErrCommandUnclosedQuote
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return args, nil
+}
+
+// AllowUsers 只允许指定 open_id 的用户执行命令
+func AllowUsers(openIds ...string) CommandMiddleware {
+	allowed := make(map[string]bool, len(openIds))
+	for _, id := range openIds {
+		allowed[id] = true
+	}
+	return func(next CommandHandler) CommandHandler {
+		return func(ctx *CommandContext) error {
+			if !allowed[ctx.SenderId().OpenId] {
+				return ErrCommandForbidden
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// AllowDepartments 只允许指定部门及其子部门的用户执行命令，部门 ID 为 open_department_id
+//
+// 子部门在首次执行时展开并缓存
+func AllowDepartments(departmentIds ...string) CommandMiddleware {
+	var mu sync.Mutex
+	var allowed map[string]bool
+	expand := func(ctx *CommandContext) (map[string]bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if allowed != nil {
+			return allowed, nil
+		}
+		m := make(map[string]bool)
+		for _, id := range departmentIds {
+			ids, err := ctx.Client.fanOutDepartments(ctx, id, "open_department_id")
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				m[id] = true
+			}
+		}
+		allowed = m
+		return allowed, nil
+	}
+
+	return func(next CommandHandler) CommandHandler {
+		return func(ctx *CommandContext) error {
+			allowed, err := expand(ctx)
+			if err != nil {
+				return err
+			}
+			res, err := ctx.Client.GetUsers(GetUsersParam{
+				UserId:           ctx.SenderId().OpenId,
+				UserIdType:       ReceiveIdTypeOpenId,
+				DepartmentIdType: "open_department_id",
+			})
+			if err != nil {
+				return err
+			}
+			if res.Code != 0 {
+				return fmt.Errorf("get users code %d msg %s", res.Code, res.Msg)
+			}
+			for _, id := range res.Data.User.DepartmentIds {
+				if allowed[id] {
+					return next(ctx)
+				}
+			}
+			return ErrCommandForbidden
+		}
+	}
+}
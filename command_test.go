@@ -0,0 +1,176 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommandArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+		err  error
+	}{
+		{in: ` deploy  svc --env prod `, want: []string{"deploy", "svc", "--env", "prod"}},
+		{in: `say "hello world" 'it''s' a\ b`, want: []string{"say", "hello world", "its", "a b"}},
+		{in: `say “你好 世界” ""`, want: []string{"say", "你好 世界", ""}},
+		{in: `say "oops`, err: ErrCommandUnclosedQuote},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommandArgs(tt.in)
+		if err != tt.err || (err == nil && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("SplitCommandArgs(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestCommandRouter(t *testing.T) {
+	var replies []string
+	var inThread []bool
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var param ReplyMessagesParam
+		_ = json.NewDecoder(r.Body).Decode(&param)
+		var content TextContent
+		_ = json.Unmarshal([]byte(param.Content), &content)
+		replies = append(replies, content.Text)
+		inThread = append(inThread, param.ReplyInThread)
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}))
+
+	router := NewCommandRouter(client)
+	router.BotOpenId = "ou_bot"
+	deploy := router.Command(&Command{
+		Name:        "deploy",
+		Usage:       "<service>",
+		Description: "部署服务",
+		Flags:       []CommandFlag{{Name: "env", Default: "staging"}, {Name: "force", Bool: true}},
+		Handler: func(ctx *CommandContext) error {
+			return ctx.ReplyText(strings.Join(ctx.Args, ",") + " " + ctx.Flag("env") + " " + ctx.Flags["force"])
+		},
+	})
+	deploy.Command(&Command{
+		Name:        "rollback",
+		Middlewares: []CommandMiddleware{AllowUsers("ou_admin")},
+		Handler: func(ctx *CommandContext) error {
+			return ctx.ReplyText("rollback " + ctx.Args[0])
+		},
+	})
+	d := NewEventDispatcher()
+	router.Register(d)
+
+	threadId := ""
+	send := func(chatType, text string, mentions ...MessageReceiveEventMention) {
+		content, _ := json.Marshal(map[string]string{"text": text})
+		data, _ := json.Marshal(MessageReceiveEvent{
+			Sender: MessageReceiveEventSender{SenderId: EventUserId{OpenId: "ou_user"}},
+			Message: MessageReceiveEventMessage{
+				MessageId:   "om_1",
+				ThreadId:    threadId,
+				ChatType:    chatType,
+				MessageType: MsgTypeText,
+				Content:     string(content),
+				Mentions:    mentions,
+			},
+		})
+		event := &Event{Header: EventHeader{EventType: EventTypeMessageReceive}, Event: data}
+		if err := d.Dispatch(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bot := MessageReceiveEventMention{Key: "@_user_1", Id: EventUserId{OpenId: "ou_bot"}}
+
+	send("group", "@_user_1 deploy service-x --env prod --force", bot)
+	send("group", "deploy service-x")
+	send("p2p", "/deploy a -- --b")
+	send("p2p", "deploy rollback 'svc a'")
+	send("p2p", "help deploy")
+	send("p2p", "unknown")
+	threadId = "omt_1"
+	send("group", "@_user_1 deploy service-y", bot)
+
+	want := []string{
+		"service-x prod true",
+		"a,--b staging false",
+		"没有权限执行该命令",
+		deploy.Help(),
+		"未知命令 unknown\n" + router.Help(),
+		"service-y staging false",
+	}
+	if !reflect.DeepEqual(replies, want) {
+		t.Errorf("replies = %q, want %q", replies, want)
+	}
+	if inThread[0] || !inThread[len(inThread)-1] {
+		t.Errorf("reply_in_thread = %v", inThread)
+	}
+	if !strings.Contains(deploy.Help(), "deploy rollback") || !strings.Contains(deploy.Help(), "--env <值>") {
+		t.Errorf("Help() = %s", deploy.Help())
+	}
+}
+
+func TestCommandParse(t *testing.T) {
+	cmd := &Command{Name: "scale", Flags: []CommandFlag{{Name: "count"}, {Name: "dry", Bool: true}}}
+	tests := []struct {
+		in    []string
+		args  []string
+		count string
+		dry   string
+	}{
+		{in: []string{"svc", "-5"}, args: []string{"svc", "-5"}, dry: "false"},
+		{in: []string{"-5", "--dry", "svc"}, args: []string{"-5", "svc"}, dry: "true"},
+		{in: []string{"--count", "-3", "svc", "-1.5"}, args: []string{"svc", "-1.5"}, count: "-3", dry: "false"},
+		{in: []string{"--dry", "-2", "--count=4"}, args: []string{"-2"}, count: "4", dry: "true"},
+	}
+	for _, tt := range tests {
+		args, flags, err := cmd.parse(tt.in)
+		if err != nil || !reflect.DeepEqual(args, tt.args) || flags["count"] != tt.count || flags["dry"] != tt.dry {
+			t.Errorf("parse(%q) = %q, %v, %v", tt.in, args, flags, err)
+		}
+	}
+}
+
+func TestCommandRouterWithoutBotOpenId(t *testing.T) {
+	var replies int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		replies++
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}))
+	router := NewCommandRouter(client)
+	router.Handle("ping", "", func(ctx *CommandContext) error { return ctx.ReplyText("pong") })
+
+	send := func(chatType, text string, mentions ...MessageReceiveEventMention) {
+		content, _ := json.Marshal(map[string]string{"text": text})
+		data := &MessageReceiveEvent{Message: MessageReceiveEventMessage{
+			MessageId: "om_1", ChatType: chatType, MessageType: MsgTypeText, Content: string(content), Mentions: mentions,
+		}}
+		if err := router.HandleMessage(context.Background(), nil, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send("group", "大家好")
+	send("group", "ping")
+	if replies != 0 {
+		t.Fatalf("replied %d times to group messages without mention", replies)
+	}
+	send("group", "@_user_1 ping", MessageReceiveEventMention{Key: "@_user_1"})
+	send("p2p", "ping")
+	if replies != 2 {
+		t.Errorf("replies = %d, want 2", replies)
+	}
+}
+
+func TestCommandContextZeroValue(t *testing.T) {
+	var c CommandContext
+	if id := c.SenderId(); id != (EventUserId{}) {
+		t.Errorf("SenderId() = %+v", id)
+	}
+	if c.ChatId() != "" {
+		t.Errorf("ChatId() = %s", c.ChatId())
+	}
+	if _, ok := c.Mention("@_user_1"); ok {
+		t.Error("Mention() ok on zero context")
+	}
+}
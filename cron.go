@@ -0,0 +1,150 @@
+package feishu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule 五段式 cron 表达式：分 时 日 月 周
+//
+// 每段支持 *、数字、a-b 范围、/n 步长与逗号分隔的列表，周日为 0 或 7；
+// 日与周同时限定时满足其一即可。另支持 @hourly、@daily、@weekly、@monthly
+type CronSchedule struct {
+	spec   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	anyDom bool
+	anyDow bool
+}
+
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron 解析 cron 表达式
+func ParseCron(spec string) (*CronSchedule, error) {
+	expr := strings.TrimSpace(spec)
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("feishu: cron %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	s := &CronSchedule{spec: spec}
+	bounds := []struct {
+		field    *uint64
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		bits, err := parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("feishu: cron %q: %w", spec, err)
+		}
+		*b.field = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.anyDom = fields[2] == "*"
+	s.anyDow = fields[4] == "*"
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// String 返回原始表达式
+func (s *CronSchedule) String() string {
+	return s.spec
+}
+
+// Next 返回 t 之后的下一次执行时间，使用 t 的时区；五年内没有匹配时返回零值
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dow
+	case s.anyDow:
+		return dom
+	}
+	return dom || dow
+}
@@ -0,0 +1,39 @@
+package feishu
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	from := time.Date(2021, 3, 5, 10, 30, 20, 0, time.UTC) // 周五
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2021, 3, 5, 10, 31, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2021, 3, 6, 9, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, 3, 5, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2021, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2021, 3, 7, 9, 0, 0, 0, time.UTC)},
+		{"30 8 1,15 * *", time.Date(2021, 3, 15, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+		{"@daily", time.Date(2021, 3, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.spec, err)
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next() = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) expected error", spec)
+		}
+	}
+}
@@ -15,6 +15,12 @@
 package feishu
 
 import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
 	"github.com/fengid/feishu/util"
 )
 
@@ -45,3 +51,39 @@ func (c *Crypto) GetDecryptMsg(encryptMsg string) (decryptMsg []byte, err error)
 
 	return
 }
+
+// VerifySignature 校验事件回调签名
+//
+// 未配置 EncryptKey 时不做校验；配置后请求必须携带正确的签名
+func (c *Crypto) VerifySignature(header http.Header, body []byte) bool {
+	if c.EncryptKey == "" {
+		return true
+	}
+	signature := header.Get("X-Lark-Signature")
+	if signature == "" {
+		return false
+	}
+
+	h := sha256.New()
+	h.Write([]byte(header.Get("X-Lark-Request-Timestamp") + header.Get("X-Lark-Request-Nonce") + c.EncryptKey))
+	h.Write(body)
+	expected := hex.EncodeToString(h.Sum(nil))
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
+
+// VerifyCardSignature 校验消息卡片回调签名
+//
+// 卡片回调使用 VerificationToken 参与签名，未配置 VerificationToken 时不做校验
+func (c *Crypto) VerifyCardSignature(header http.Header, body []byte) bool {
+	if c.VerificationToken == "" {
+		return true
+	}
+
+	h := sha1.New()
+	h.Write([]byte(header.Get("X-Lark-Request-Timestamp") + header.Get("X-Lark-Request-Nonce") + c.VerificationToken))
+	h.Write(body)
+	expected := hex.EncodeToString(h.Sum(nil))
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(header.Get("X-Lark-Signature"))) == 1
+}
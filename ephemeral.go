@@ -0,0 +1,95 @@
+package feishu
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// SendEphemeralParam 发送仅特定人可见的消息卡片的请求结构体，仅支持群聊
+//
+// OpenId、UserId、Email 三选一
+type SendEphemeralParam struct {
+	ChatId  string `json:"chat_id"`
+	OpenId  string `json:"open_id,omitempty"`
+	UserId  string `json:"user_id,omitempty"`
+	Email   string `json:"email,omitempty"`
+	MsgType string `json:"msg_type"`
+	Card    *Card  `json:"card"`
+}
+
+// NewSendEphemeralParam 创建发送给群内指定用户的临时卡片参数
+func NewSendEphemeralParam(chatId, openId string, card *Card) SendEphemeralParam {
+	return SendEphemeralParam{
+		ChatId:  chatId,
+		OpenId:  openId,
+		MsgType: MsgTypeInteractive,
+		Card:    card,
+	}
+}
+
+// SendEphemeralRes 发送仅特定人可见的消息卡片的响应结构体
+type SendEphemeralRes struct {
+	ResponseCode
+	Data struct {
+		MessageId string `json:"message_id"`
+	} `json:"data"`
+}
+
+// SendEphemeral 发送仅特定人可见的消息卡片
+func (c *Client) SendEphemeral(param SendEphemeralParam) (*SendEphemeralRes, error) {
+	if param.MsgType == "" {
+		param.MsgType = MsgTypeInteractive
+	}
+	jsonStr, err := json.Marshal(param)
+	if err != nil {
+		return nil, err
+	}
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/ephemeral/v1/send", strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data SendEphemeralRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// DeleteEphemeralParam 删除仅特定人可见的消息卡片的请求结构体
+type DeleteEphemeralParam struct {
+	MessageId string `json:"message_id"`
+}
+
+// DeleteEphemeralRes 删除仅特定人可见的消息卡片的响应结构体
+type DeleteEphemeralRes struct {
+	ResponseCode
+}
+
+// DeleteEphemeral 删除仅特定人可见的消息卡片
+func (c *Client) DeleteEphemeral(param DeleteEphemeralParam) (*DeleteEphemeralRes, error) {
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/ephemeral/v1/delete", strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data DeleteEphemeralRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
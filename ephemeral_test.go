@@ -0,0 +1,38 @@
+package feishu
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestEphemeral(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/open-apis/ephemeral/v1/send":
+			card, _ := body["card"].(map[string]interface{})
+			if body["chat_id"] != "oc_1" || body["open_id"] != "ou_1" || body["msg_type"] != MsgTypeInteractive || card["elements"] == nil {
+				t.Errorf("send body = %v", body)
+			}
+			_, _ = w.Write([]byte(`{"code":0,"msg":"ok","data":{"message_id":"om_e1"}}`))
+		case "/open-apis/ephemeral/v1/delete":
+			if body["message_id"] != "om_e1" {
+				t.Errorf("delete body = %v", body)
+			}
+			_, _ = w.Write([]byte(`{"code":0,"msg":"ok"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+
+	card, _ := NewCardBuilder().Markdown("只有你能看到").Build()
+	res, err := client.SendEphemeral(NewSendEphemeralParam("oc_1", "ou_1", card))
+	if err != nil || res.Code != 0 || res.Data.MessageId != "om_e1" {
+		t.Fatalf("SendEphemeral() = %+v, %v", res, err)
+	}
+	if res, err := client.DeleteEphemeral(DeleteEphemeralParam{MessageId: res.Data.MessageId}); err != nil || res.Code != 0 {
+		t.Fatalf("DeleteEphemeral() = %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,322 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// 事件类型
+const (
+	EventTypeMessageReceive = "im.message.receive_v1" // 接收消息
+)
+
+var (
+	ErrEventTokenMismatch = errors.New("feishu: event verification token mismatch")
+	ErrEventSignature     = errors.New("feishu: event signature mismatch")
+	ErrEventNotEncrypted  = errors.New("feishu: event body is not encrypted")
+)
+
+// EventHeader 事件头 (2.0)
+type EventHeader struct {
+	EventId    string `json:"event_id"`
+	EventType  string `json:"event_type"`
+	CreateTime string `json:"create_time"`
+	Token      string `json:"token"`
+	AppId      string `json:"app_id"`
+	TenantKey  string `json:"tenant_key"`
+}
+
+// Event 事件回调
+//
+// 1.0 版本的事件会被转换为 2.0 的结构，Event 为原始的事件体
+type Event struct {
+	Schema string          `json:"schema"`
+	Header EventHeader     `json:"header"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// ChatId 事件所属会话，非会话相关事件返回空
+func (e *Event) ChatId() string {
+	var body struct {
+		OpenChatId string `json:"open_chat_id"`
+		ChatId     string `json:"chat_id"`
+		Message    struct {
+			ChatId string `json:"chat_id"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(e.Event, &body); err != nil {
+		return ""
+	}
+	switch {
+	case body.Message.ChatId != "":
+		return body.Message.ChatId
+	case body.ChatId != "":
+		return body.ChatId
+	}
+	return body.OpenChatId
+}
+
+// EventUserId 事件中的用户 ID
+type EventUserId struct {
+	UnionId string `json:"union_id"`
+	UserId  string `json:"user_id"`
+	OpenId  string `json:"open_id"`
+}
+
+// MessageReceiveEvent 接收消息事件体
+type MessageReceiveEvent struct {
+	Sender  MessageReceiveEventSender  `json:"sender"`
+	Message MessageReceiveEventMessage `json:"message"`
+}
+
+type MessageReceiveEventSender struct {
+	SenderId   EventUserId `json:"sender_id"`
+	SenderType string      `json:"sender_type"`
+	TenantKey  string      `json:"tenant_key"`
+}
+
+type MessageReceiveEventMessage struct {
+	MessageId   string                       `json:"message_id"`
+	RootId      string                       `json:"root_id"`
+	ParentId    string                       `json:"parent_id"`
+	ThreadId    string                       `json:"thread_id"` // 话题 ID，话题群或话题中的消息才有
+	CreateTime  string                       `json:"create_time"`
+	ChatId      string                       `json:"chat_id"`
+	ChatType    string                       `json:"chat_type"`
+	MessageType string                       `json:"message_type"`
+	Content     string                       `json:"content"`
+	Mentions    []MessageReceiveEventMention `json:"mentions"`
+}
+
+type MessageReceiveEventMention struct {
+	Key       string      `json:"key"`
+	Id        EventUserId `json:"id"`
+	Name      string      `json:"name"`
+	TenantKey string      `json:"tenant_key"`
+}
+
+// EventSink 事件投递目标
+type EventSink interface {
+	Dispatch(ctx context.Context, event *Event) error
+}
+
+// EventHandlerFunc 事件处理函数
+type EventHandlerFunc func(ctx context.Context, event *Event) error
+
+// Dispatch 使 EventHandlerFunc 可以直接作为 EventSink
+func (f EventHandlerFunc) Dispatch(ctx context.Context, event *Event) error {
+	return f(ctx, event)
+}
+
+// EventDispatcher 按事件类型分发事件
+type EventDispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string]EventHandlerFunc
+	fallback EventHandlerFunc
+}
+
+// NewEventDispatcher 创建事件分发器
+func NewEventDispatcher() *EventDispatcher {
+	return &EventDispatcher{
+		handlers: map[string]EventHandlerFunc{},
+	}
+}
+
+// On 注册事件处理函数
+func (d *EventDispatcher) On(eventType string, handler EventHandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[eventType] = handler
+}
+
+// OnUnhandled 注册未匹配事件的处理函数
+func (d *EventDispatcher) OnUnhandled(handler EventHandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.fallback = handler
+}
+
+// OnMessageReceive 注册接收消息事件处理函数
+func (d *EventDispatcher) OnMessageReceive(handler func(ctx context.Context, event *Event, data *MessageReceiveEvent) error) {
+	d.On(EventTypeMessageReceive, func(ctx context.Context, event *Event) error {
+		var data MessageReceiveEvent
+		if err := json.Unmarshal(event.Event, &data); err != nil {
+			return err
+		}
+		return handler(ctx, event, &data)
+	})
+}
+
+// Dispatch 分发事件
+func (d *EventDispatcher) Dispatch(ctx context.Context, event *Event) error {
+	d.mu.RLock()
+	handler, ok := d.handlers[event.Header.EventType]
+	if !ok {
+		handler = d.fallback
+	}
+	d.mu.RUnlock()
+
+	if handler == nil {
+		if Logger != nil {
+			Logger.Printf("unhandled event %s %s", event.Header.EventType, event.Header.EventId)
+		}
+		return nil
+	}
+	return handler(ctx, event)
+}
+
+// ParseEvent 解析事件回调请求体
+//
+// 返回值 challenge 不为空时表示这是一次 url_verification 请求
+func ParseEvent(crypto *Crypto, body []byte) (event *Event, challenge string, err error) {
+	var encrypted struct {
+		Encrypt string `json:"encrypt"`
+	}
+	if err = json.Unmarshal(body, &encrypted); err != nil {
+		return
+	}
+	if encrypted.Encrypt == "" && crypto != nil && crypto.EncryptKey != "" {
+		// 配置 EncryptKey 后飞书推送的请求均已加密，明文请求视为伪造
+		err = ErrEventNotEncrypted
+		return
+	}
+	if encrypted.Encrypt != "" {
+		if crypto == nil || crypto.EncryptKey == "" {
+			err = errors.New("feishu: encrypted event without encrypt key")
+			return
+		}
+		body, err = crypto.GetDecryptMsg(encrypted.Encrypt)
+		if err != nil {
+			return
+		}
+	}
+
+	var raw struct {
+		Schema    string          `json:"schema"`
+		Header    EventHeader     `json:"header"`
+		Event     json.RawMessage `json:"event"`
+		Type      string          `json:"type"`
+		Token     string          `json:"token"`
+		Challenge string          `json:"challenge"`
+		Uuid      string          `json:"uuid"`
+		Ts        string          `json:"ts"`
+	}
+	if err = json.Unmarshal(body, &raw); err != nil {
+		err = fmt.Errorf("feishu: unmarshal event: %w", err)
+		return
+	}
+
+	token := raw.Header.Token
+	if raw.Schema == "" {
+		token = raw.Token
+	}
+	if crypto != nil && crypto.VerificationToken != "" && token != crypto.VerificationToken {
+		err = ErrEventTokenMismatch
+		return
+	}
+
+	if raw.Type == "url_verification" {
+		challenge = raw.Challenge
+		return
+	}
+
+	event = &Event{
+		Schema: raw.Schema,
+		Header: raw.Header,
+		Event:  raw.Event,
+	}
+
+	// 1.0 事件
+	if raw.Schema == "" {
+		var v1 struct {
+			Type      string `json:"type"`
+			AppId     string `json:"app_id"`
+			TenantKey string `json:"tenant_key"`
+		}
+		_ = json.Unmarshal(raw.Event, &v1)
+		event.Header = EventHeader{
+			EventId:    raw.Uuid,
+			EventType:  v1.Type,
+			CreateTime: raw.Ts,
+			Token:      raw.Token,
+			AppId:      v1.AppId,
+			TenantKey:  v1.TenantKey,
+		}
+	}
+
+	return
+}
+
+// EventHandler 事件回调 http.Handler
+//
+// 完成 url_verification、签名校验、解密后将事件交给 Sink，
+// Sink 为 AsyncDispatcher 时可以立即应答飞书
+type EventHandler struct {
+	Crypto *Crypto
+	Sink   EventSink
+}
+
+// NewEventHandler 创建事件回调处理器
+func NewEventHandler(crypto *Crypto, sink EventSink) *EventHandler {
+	return &EventHandler{
+		Crypto: crypto,
+		Sink:   sink,
+	}
+}
+
+// ServeHTTP 处理事件回调
+func (h *EventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	verified := h.Crypto == nil || h.Crypto.VerifySignature(r.Header, body)
+	if !verified && r.Header.Get("X-Lark-Signature") != "" {
+		http.Error(w, ErrEventSignature.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event, challenge, err := ParseEvent(h.Crypto, body)
+	if err != nil {
+		if Logger != nil {
+			Logger.Printf("ParseEvent error %s", err)
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 未签名的请求只允许加密的 url_verification，应答 challenge 不会触发任何处理
+	if !verified && event != nil {
+		http.Error(w, ErrEventSignature.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeApplicationJson)
+	if event == nil {
+		resp, _ := json.Marshal(map[string]string{"challenge": challenge})
+		_, _ = w.Write(resp)
+		return
+	}
+
+	if err = h.Sink.Dispatch(r.Context(), event); err != nil {
+		if Logger != nil {
+			Logger.Printf("Dispatch %s %s error %s", event.Header.EventType, event.Header.EventId, err)
+		}
+		// 非 200 响应飞书会重新推送
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrQueueFull) || errors.Is(err, ErrDispatcherClosed) {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	_, _ = w.Write([]byte(`{}`))
+}
@@ -0,0 +1,177 @@
+package feishu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueueFull        = errors.New("feishu: event queue is full")
+	ErrDispatcherClosed = errors.New("feishu: event dispatcher is closed")
+)
+
+// AsyncDispatcherConfig 异步分发配置
+type AsyncDispatcherConfig struct {
+	Workers    int                             // worker 数量，默认 4
+	QueueSize  int                             // 每个 worker 的队列长度，默认 100
+	MaxRetries int                             // 处理失败后的最大重试次数
+	Backoff    func(attempt int) time.Duration // 第 attempt 次重试前的等待时间，默认指数退避
+	DeadLetter func(event *Event, err error)   // 重试耗尽后的回调
+}
+
+// AsyncDispatcher 异步事件分发器
+//
+// Dispatch 只负责入队，事件由 worker 交给 Sink 处理。
+// 同一会话的事件总是由同一个 worker 顺序处理，重试也不会打乱顺序
+type AsyncDispatcher struct {
+	sink   EventSink
+	config AsyncDispatcherConfig
+
+	queues []chan *Event
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewAsyncDispatcher 创建并启动异步事件分发器
+func NewAsyncDispatcher(sink EventSink, config AsyncDispatcherConfig) *AsyncDispatcher {
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 100
+	}
+	if config.Backoff == nil {
+		config.Backoff = defaultBackoff
+	}
+
+	a := &AsyncDispatcher{
+		sink:   sink,
+		config: config,
+		queues: make([]chan *Event, config.Workers),
+	}
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+
+	for i := range a.queues {
+		a.queues[i] = make(chan *Event, config.QueueSize)
+		a.wg.Add(1)
+		go a.work(a.queues[i])
+	}
+
+	return a
+}
+
+// Dispatch 事件入队，队列已满时返回 ErrQueueFull
+func (a *AsyncDispatcher) Dispatch(ctx context.Context, event *Event) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrDispatcherClosed
+	}
+
+	select {
+	case a.queues[a.shard(event)] <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown 停止接收新事件并等待已入队的事件处理完毕
+//
+// ctx 结束时取消正在处理的事件并立即返回 ctx.Err()，未处理完的事件在后台继续退出
+func (a *AsyncDispatcher) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		for _, q := range a.queues {
+			close(q)
+		}
+	}
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		a.cancel()
+		return nil
+	case <-ctx.Done():
+		a.cancel()
+		return ctx.Err()
+	}
+}
+
+// shard 按会话选择 worker，无会话的事件按事件 ID 打散
+func (a *AsyncDispatcher) shard(event *Event) int {
+	key := event.ChatId()
+	if key == "" {
+		key = event.Header.EventId
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(a.queues)))
+}
+
+func (a *AsyncDispatcher) work(queue chan *Event) {
+	defer a.wg.Done()
+	for event := range queue {
+		a.process(event)
+	}
+}
+
+func (a *AsyncDispatcher) process(event *Event) {
+	for attempt := 0; ; attempt++ {
+		err := a.handle(event)
+		if err == nil {
+			return
+		}
+
+		if Logger != nil {
+			Logger.Printf("event %s %s attempt %d error %s", event.Header.EventType, event.Header.EventId, attempt+1, err)
+		}
+
+		if attempt >= a.config.MaxRetries || a.ctx.Err() != nil {
+			if a.config.DeadLetter != nil {
+				a.config.DeadLetter(event, err)
+			}
+			return
+		}
+
+		select {
+		case <-time.After(a.config.Backoff(attempt)):
+		case <-a.ctx.Done():
+		}
+	}
+}
+
+func (a *AsyncDispatcher) handle(event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return a.sink.Dispatch(a.ctx, event)
+}
+
+// defaultBackoff 指数退避，从 500ms 开始，最长 30s
+func defaultBackoff(attempt int) time.Duration {
+	d := 500 * time.Millisecond << uint(attempt)
+	if d <= 0 || d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d
+}
@@ -0,0 +1,266 @@
+package feishu
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fengid/feishu/util"
+)
+
+// testEncrypt 按飞书事件加密方式加密，IV 固定为零值
+func testEncrypt(key, plaintext string) string {
+	sum := sha256.Sum256([]byte(key))
+	block, _ := aes.NewCipher(sum[:])
+	padded := util.PKCS5Padding([]byte(plaintext), aes.BlockSize)
+	ciphertext := make([]byte, aes.BlockSize+len(padded))
+	cipher.NewCBCEncrypter(block, ciphertext[:aes.BlockSize]).CryptBlocks(ciphertext[aes.BlockSize:], padded)
+	return `{"encrypt":"` + base64.StdEncoding.EncodeToString(ciphertext) + `"}`
+}
+
+// testSign 按飞书事件签名方式签名
+func testSign(r *http.Request, key, body string) {
+	r.Header.Set("X-Lark-Request-Timestamp", "1")
+	r.Header.Set("X-Lark-Request-Nonce", "n")
+	sum := sha256.Sum256([]byte("1" + "n" + key + body))
+	r.Header.Set("X-Lark-Signature", hex.EncodeToString(sum[:]))
+}
+
+func TestParseEvent(t *testing.T) {
+	crypto := &Crypto{EncryptKey: "test key", VerificationToken: "token"}
+	tokenOnly := &Crypto{VerificationToken: "token"}
+	v2 := `{"schema":"2.0","header":{"event_id":"e1","event_type":"im.message.receive_v1","token":"token"},"event":{"message":{"chat_id":"oc_1"}}}`
+
+	tests := []struct {
+		name          string
+		crypto        *Crypto
+		body          string
+		wantChallenge string
+		wantType      string
+		wantEventId   string
+		wantErr       bool
+	}{
+		{
+			name:          "challenge",
+			crypto:        tokenOnly,
+			body:          `{"challenge":"ajls384kdjx98XX","token":"token","type":"url_verification"}`,
+			wantChallenge: "ajls384kdjx98XX",
+		},
+		{
+			name:        "v2",
+			crypto:      tokenOnly,
+			body:        v2,
+			wantType:    EventTypeMessageReceive,
+			wantEventId: "e1",
+		},
+		{
+			name:        "encrypted v2",
+			crypto:      crypto,
+			body:        testEncrypt("test key", v2),
+			wantType:    EventTypeMessageReceive,
+			wantEventId: "e1",
+		},
+		{
+			name:    "plaintext with encrypt key",
+			crypto:  crypto,
+			body:    v2,
+			wantErr: true,
+		},
+		{
+			name:    "truncated ciphertext",
+			crypto:  crypto,
+			body:    `{"encrypt":"` + base64.StdEncoding.EncodeToString(make([]byte, 20)) + `"}`,
+			wantErr: true,
+		},
+		{
+			name:        "v1",
+			crypto:      nil,
+			body:        `{"uuid":"u1","token":"t","ts":"1","type":"event_callback","event":{"type":"message","app_id":"cli"}}`,
+			wantType:    "message",
+			wantEventId: "u1",
+		},
+		{
+			name:    "token mismatch",
+			crypto:  crypto,
+			body:    `{"schema":"2.0","header":{"event_id":"e1","event_type":"x","token":"bad"},"event":{}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, challenge, err := ParseEvent(tt.crypto, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if challenge != tt.wantChallenge {
+				t.Errorf("ParseEvent() challenge = %v, want %v", challenge, tt.wantChallenge)
+			}
+			if tt.wantType != "" && (event.Header.EventType != tt.wantType || event.Header.EventId != tt.wantEventId) {
+				t.Errorf("ParseEvent() header = %+v", event.Header)
+			}
+		})
+	}
+}
+
+func TestEventHandler(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	got := make(chan string, 1)
+	dispatcher.OnMessageReceive(func(ctx context.Context, event *Event, data *MessageReceiveEvent) error {
+		got <- data.Message.ChatId
+		return nil
+	})
+	handler := NewEventHandler(nil, dispatcher)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"type":"url_verification","challenge":"c1"}`)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"challenge":"c1"`) {
+		t.Fatalf("challenge response %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"schema":"2.0","header":{"event_type":"im.message.receive_v1"},"event":{"message":{"chat_id":"oc_1"}}}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("event response %d %s", w.Code, w.Body.String())
+	}
+	if chatId := <-got; chatId != "oc_1" {
+		t.Errorf("chat_id = %s", chatId)
+	}
+}
+
+func TestEventHandlerSignature(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+	var dispatched int
+	dispatcher.OnMessageReceive(func(ctx context.Context, event *Event, data *MessageReceiveEvent) error {
+		dispatched++
+		return nil
+	})
+	handler := NewEventHandler(&Crypto{EncryptKey: "test key"}, dispatcher)
+
+	event := testEncrypt("test key", `{"schema":"2.0","header":{"event_type":"im.message.receive_v1"},"event":{"message":{"chat_id":"oc_1"}}}`)
+	tests := []struct {
+		name     string
+		body     string
+		sign     bool
+		wantCode int
+	}{
+		{"forged unsigned plaintext", `{"schema":"2.0","header":{"event_type":"im.message.receive_v1"},"event":{"message":{"chat_id":"oc_1"}}}`, false, http.StatusBadRequest},
+		{"unsigned encrypted event", event, false, http.StatusUnauthorized},
+		{"signed encrypted event", event, true, http.StatusOK},
+		{"unsigned encrypted challenge", testEncrypt("test key", `{"type":"url_verification","challenge":"c1"}`), false, http.StatusOK},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+		if tt.sign {
+			testSign(r, "test key", tt.body)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: response %d %s", tt.name, w.Code, w.Body.String())
+		}
+	}
+	if dispatched != 1 {
+		t.Errorf("dispatched %d events, want 1", dispatched)
+	}
+}
+
+func TestAsyncDispatcher(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	attempts := map[string]int{}
+
+	sink := EventHandlerFunc(func(ctx context.Context, event *Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[event.Header.EventId]++
+		if event.Header.EventId == "fail" {
+			return errors.New("always")
+		}
+		if event.Header.EventId == "flaky" && attempts["flaky"] < 2 {
+			return errors.New("once")
+		}
+		order = append(order, event.Header.EventId)
+		return nil
+	})
+
+	var dead []string
+	a := NewAsyncDispatcher(sink, AsyncDispatcherConfig{
+		Workers:    4,
+		MaxRetries: 2,
+		Backoff:    func(int) time.Duration { return time.Millisecond },
+		DeadLetter: func(event *Event, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			dead = append(dead, event.Header.EventId)
+		},
+	})
+
+	for _, id := range []string{"1", "flaky", "2", "fail", "3"} {
+		event := &Event{Header: EventHeader{EventId: id}, Event: []byte(`{"message":{"chat_id":"oc_1"}}`)}
+		if err := a.Dispatch(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := a.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Dispatch(context.Background(), &Event{}); err != ErrDispatcherClosed {
+		t.Errorf("Dispatch() after Shutdown error = %v", err)
+	}
+
+	if strings.Join(order, ",") != "1,flaky,2,3" {
+		t.Errorf("order = %v", order)
+	}
+	if len(dead) != 1 || dead[0] != "fail" || attempts["fail"] != 3 {
+		t.Errorf("dead = %v, attempts = %v", dead, attempts)
+	}
+}
+
+func TestAsyncDispatcherShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	a := NewAsyncDispatcher(EventHandlerFunc(func(ctx context.Context, event *Event) error {
+		close(started)
+		// 忽略 ctx 的处理函数
+		<-release
+		return nil
+	}), AsyncDispatcherConfig{Workers: 1})
+
+	if err := a.Dispatch(context.Background(), &Event{Header: EventHeader{EventId: "slow"}}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() { errc <- a.Shutdown(ctx) }()
+	select {
+	case err := <-errc:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown() blocked after ctx deadline")
+	}
+}
+
+func TestParseEventErrorHidesBody(t *testing.T) {
+	crypto := &Crypto{EncryptKey: "test key"}
+	_, _, err := ParseEvent(crypto, []byte(testEncrypt("test key", "secret payload")))
+	if err == nil || strings.Contains(err.Error(), "secret payload") {
+		t.Errorf("ParseEvent() error = %v", err)
+	}
+}
@@ -0,0 +1,303 @@
+package feishu
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 发送消息频率超限的错误码
+const (
+	CodeFrequencyLimit        = 99991400
+	CodeMessageFrequencyLimit = 230020
+)
+
+// FanOutMaxRateLimit 群发每秒最多发送条数的上限，超过时按上限发送
+const FanOutMaxRateLimit = 1000
+
+// FanOutParam 组织架构感知的群发请求结构体
+//
+// 部门会递归展开为其下所有在职且未冻结的用户，所有接收者按 open_id、email、chat_id 去重
+type FanOutParam struct {
+	DepartmentIds    []string
+	DepartmentIdType string // department_id 或 open_department_id，默认 open_department_id
+	ChatIds          []string
+	Emails           []string
+	OpenIds          []string
+	Content          MessageContent
+	Concurrency      int                             // 并发发送数，默认 5
+	RateLimit        int                             // 每秒最多发送条数，默认 50，最大 FanOutMaxRateLimit
+	MaxRetries       int                             // 频率超限或网络错误时的最大重试次数
+	Backoff          func(attempt int) time.Duration // 第 attempt 次重试前的等待时间，默认指数退避
+}
+
+// FanOutRecipient 群发接收者
+type FanOutRecipient struct {
+	ReceiveIdType string
+	ReceiveId     string
+	Name          string // 由部门展开时为用户名
+	DepartmentId  string // 由部门展开时为来源部门
+}
+
+// FanOutResult 单个接收者的投递结果
+type FanOutResult struct {
+	FanOutRecipient
+	MessageId string
+	Code      int64
+	Msg       string
+	Err       error
+}
+
+// Ok 是否投递成功
+func (r FanOutResult) Ok() bool {
+	return r.Err == nil && r.Code == 0
+}
+
+// FanOutSkipped 展开部门时跳过的用户
+type FanOutSkipped struct {
+	OpenId       string
+	Name         string
+	DepartmentId string
+	Reason       string // resigned 或 frozen
+}
+
+// FanOutReport 群发投递报告，Results 与去重后的接收者顺序一致
+type FanOutReport struct {
+	Results []FanOutResult
+	Skipped []FanOutSkipped
+}
+
+// Failed 返回投递失败的结果
+func (r *FanOutReport) Failed() []FanOutResult {
+	var failed []FanOutResult
+	for _, result := range r.Results {
+		if !result.Ok() {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// FanOut 展开部门并逐个发送消息，返回每个接收者的投递结果
+//
+// 只有展开部门失败或 ctx 取消时返回 error，单个接收者的失败记录在报告中
+func (c *Client) FanOut(ctx context.Context, param FanOutParam) (*FanOutReport, error) {
+	if param.Content == nil {
+		return nil, fmt.Errorf("feishu: fan out content is empty")
+	}
+	content, err := MarshalMessageContent(param.Content)
+	if err != nil {
+		return nil, err
+	}
+	if param.DepartmentIdType == "" {
+		param.DepartmentIdType = "open_department_id"
+	}
+	if param.Concurrency <= 0 {
+		param.Concurrency = 5
+	}
+	if param.RateLimit <= 0 {
+		param.RateLimit = 50
+	}
+	if param.RateLimit > FanOutMaxRateLimit {
+		param.RateLimit = FanOutMaxRateLimit
+	}
+	if param.Backoff == nil {
+		param.Backoff = defaultBackoff
+	}
+
+	report := &FanOutReport{}
+	recipients, err := c.fanOutRecipients(ctx, param, report)
+	if err != nil {
+		return nil, err
+	}
+	report.Results = make([]FanOutResult, len(recipients))
+
+	limiter := time.NewTicker(time.Second / time.Duration(param.RateLimit))
+	defer limiter.Stop()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < param.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				report.Results[i] = c.fanOutSend(ctx, limiter.C, param, recipients[i], content)
+			}
+		}()
+	}
+	for i := range recipients {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return report, ctx.Err()
+}
+
+// fanOutSend 发送给单个接收者，频率超限与网络错误时使用相同的 uuid 重试
+func (c *Client) fanOutSend(ctx context.Context, limiter <-chan time.Time, param FanOutParam, recipient FanOutRecipient, content string) FanOutResult {
+	result := FanOutResult{FanOutRecipient: recipient}
+	uuid := MessageUuid("")
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			result.Err = ctx.Err()
+			return result
+		case <-limiter:
+		}
+
+		res, err := c.SendMessages(SendMessagesParam{
+			ReceiveIdType: recipient.ReceiveIdType,
+			ReceiveId:     recipient.ReceiveId,
+			MsgType:       param.Content.MsgType(),
+			Content:       content,
+			Uuid:          uuid, // 重试时使用相同的 uuid，避免网络错误后重复发送
+		})
+		result.Err = err
+		if err == nil {
+			result.Code, result.Msg, result.MessageId = res.Code, res.Msg, res.Data.MessageId
+		}
+		retryable := err != nil || res.Code == CodeFrequencyLimit || res.Code == CodeMessageFrequencyLimit
+		if !retryable || attempt >= param.MaxRetries {
+			return result
+		}
+		if Logger != nil {
+			Logger.Printf("fan out %s attempt %d code %d error %v", recipient.ReceiveId, attempt+1, result.Code, err)
+		}
+		select {
+		case <-ctx.Done():
+			result.Err = ctx.Err()
+			return result
+		case <-time.After(param.Backoff(attempt)):
+		}
+	}
+}
+
+// fanOutRecipients 展开部门并去重，部门用户在前，其后依次为 open_id、email、chat_id
+func (c *Client) fanOutRecipients(ctx context.Context, param FanOutParam, report *FanOutReport) ([]FanOutRecipient, error) {
+	var recipients []FanOutRecipient
+	seen := make(map[string]bool)
+	add := func(r FanOutRecipient) {
+		key := r.ReceiveIdType + ":" + r.ReceiveId
+		if r.ReceiveId == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		recipients = append(recipients, r)
+	}
+
+	visited := make(map[string]bool)
+	for _, departmentId := range param.DepartmentIds {
+		departmentIds, err := c.fanOutDepartments(ctx, departmentId, param.DepartmentIdType)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range departmentIds {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			users, err := c.fanOutDepartmentUsers(ctx, id, param.DepartmentIdType)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range users {
+				reason := ""
+				switch {
+				case user.Status.IsResigned:
+					reason = "resigned"
+				case user.Status.IsFrozen || user.IsFrozen:
+					reason = "frozen"
+				}
+				if reason != "" {
+					report.Skipped = append(report.Skipped, FanOutSkipped{OpenId: user.OpenId, Name: user.Name, DepartmentId: id, Reason: reason})
+					continue
+				}
+				add(FanOutRecipient{ReceiveIdType: ReceiveIdTypeOpenId, ReceiveId: user.OpenId, Name: user.Name, DepartmentId: id})
+				// 部门用户的邮箱视为已覆盖，避免通过邮箱重复发送
+				for _, email := range []string{user.Email, user.EnterpriseEmail} {
+					if email != "" {
+						seen[ReceiveIdTypeEmail+":"+strings.ToLower(email)] = true
+					}
+				}
+			}
+		}
+	}
+
+	for _, openId := range param.OpenIds {
+		add(FanOutRecipient{ReceiveIdType: ReceiveIdTypeOpenId, ReceiveId: openId})
+	}
+	for _, email := range param.Emails {
+		add(FanOutRecipient{ReceiveIdType: ReceiveIdTypeEmail, ReceiveId: strings.ToLower(strings.TrimSpace(email))})
+	}
+	for _, chatId := range param.ChatIds {
+		add(FanOutRecipient{ReceiveIdType: ReceiveIdTypeChatId, ReceiveId: chatId})
+	}
+	return recipients, nil
+}
+
+// fanOutDepartments 返回部门自身及其所有未删除的子孙部门
+func (c *Client) fanOutDepartments(ctx context.Context, departmentId, departmentIdType string) ([]string, error) {
+	departmentIds := []string{departmentId}
+	for pageToken := ""; ; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res, err := c.DepartmentsChildren(DepartmentsChildrenParam{
+			DepartmentId:     departmentId,
+			DepartmentIdType: departmentIdType,
+			FetchChild:       true,
+			PageSize:         50,
+			PageToken:        pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if res.Code != 0 {
+			return nil, fmt.Errorf("departments children code %d msg %s", res.Code, res.Msg)
+		}
+		for _, item := range res.Data.Items {
+			if item.Status.IsDeleted {
+				continue
+			}
+			if departmentIdType == "department_id" {
+				departmentIds = append(departmentIds, item.DepartmentId)
+			} else {
+				departmentIds = append(departmentIds, item.OpenDepartmentId)
+			}
+		}
+		if !res.Data.HasMore || res.Data.PageToken == "" {
+			return departmentIds, nil
+		}
+		pageToken = res.Data.PageToken
+	}
+}
+
+// fanOutDepartmentUsers 返回部门直属用户
+func (c *Client) fanOutDepartmentUsers(ctx context.Context, departmentId, departmentIdType string) ([]UsersFindByDepartmentResDataItem, error) {
+	var users []UsersFindByDepartmentResDataItem
+	for pageToken := ""; ; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res, err := c.UsersFindByDepartment(UsersFindByDepartmentParam{
+			UserIdType:       ReceiveIdTypeOpenId,
+			DepartmentIdType: departmentIdType,
+			DepartmentId:     departmentId,
+			PageSize:         50,
+			PageToken:        pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if res.Code != 0 {
+			return nil, fmt.Errorf("users find by department code %d msg %s", res.Code, res.Msg)
+		}
+		users = append(users, res.Data.Items...)
+		if !res.Data.HasMore || res.Data.PageToken == "" {
+			return users, nil
+		}
+		pageToken = res.Data.PageToken
+	}
+}
@@ -0,0 +1,84 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFanOut(t *testing.T) {
+	var mu sync.Mutex
+	sent := make(map[string]int)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open-apis/contact/v3/departments/od_root/children":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"items":[{"open_department_id":"od_a"},{"open_department_id":"od_gone","status":{"is_deleted":true}}]}}`))
+		case "/open-apis/contact/v3/users/find_by_department":
+			switch r.URL.Query().Get("department_id") {
+			case "od_root":
+				_, _ = w.Write([]byte(`{"code":0,"data":{"items":[{"open_id":"ou_1","email":"a@example.com"},{"open_id":"ou_2","status":{"is_resigned":true}}]}}`))
+			case "od_a":
+				_, _ = w.Write([]byte(`{"code":0,"data":{"items":[{"open_id":"ou_1"},{"open_id":"ou_3","status":{"is_frozen":true}},{"open_id":"ou_4"}]}}`))
+			default:
+				t.Errorf("url = %s", r.URL)
+			}
+		case "/open-apis/im/v1/messages":
+			var param SendMessagesParam
+			_ = json.NewDecoder(r.Body).Decode(&param)
+			mu.Lock()
+			sent[param.ReceiveId]++
+			n := sent[param.ReceiveId]
+			mu.Unlock()
+			switch {
+			case param.ReceiveId == "oc_1" && n == 1:
+				_, _ = w.Write([]byte(`{"code":230020,"msg":"frequency limit"}`))
+			case param.ReceiveId == "b@example.com":
+				_, _ = w.Write([]byte(`{"code":230013,"msg":"bot has no availability to this user"}`))
+			default:
+				_, _ = w.Write([]byte(`{"code":0,"data":{"message_id":"om_` + param.ReceiveId + `"}}`))
+			}
+		default:
+			t.Errorf("url = %s", r.URL)
+		}
+	}))
+
+	report, err := client.FanOut(context.Background(), FanOutParam{
+		DepartmentIds: []string{"od_root"},
+		OpenIds:       []string{"ou_4", "ou_5"},
+		Emails:        []string{"A@example.com", "b@example.com"},
+		ChatIds:       []string{"oc_1"},
+		Content:       NewTextContent("hi"),
+		RateLimit:     2e9, // 超过上限时按 FanOutMaxRateLimit 发送，不会 panic
+		MaxRetries:    1,
+		Backoff:       func(int) time.Duration { return time.Millisecond },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, result := range report.Results {
+		got = append(got, result.ReceiveId)
+	}
+	want := []string{"ou_1", "ou_4", "ou_5", "b@example.com", "oc_1"}
+	if len(got) != len(want) {
+		t.Fatalf("recipients = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("recipients = %v, want %v", got, want)
+		}
+	}
+	if len(report.Skipped) != 2 || report.Skipped[0].Reason != "resigned" || report.Skipped[1].Reason != "frozen" {
+		t.Errorf("skipped = %+v", report.Skipped)
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].ReceiveId != "b@example.com" || failed[0].Code != 230013 {
+		t.Errorf("failed = %+v", failed)
+	}
+	if sent["oc_1"] != 2 || report.Results[4].MessageId != "om_oc_1" {
+		t.Errorf("oc_1 sent %d times, result %+v", sent["oc_1"], report.Results[4])
+	}
+}
@@ -4,5 +4,6 @@ go 1.15
 
 require (
 	github.com/faabiosr/cachego v0.15.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/json-iterator/go v1.1.12
 )
@@ -11,11 +11,42 @@ type InteractiveV1CardUpdateParam struct {
 	Card  InteractiveV1CardUpdateCardParam `json:"card"`
 }
 
+// InteractiveV1CardUpdateCardParam 更新后的卡片，OpenIds 为空时更新所有人的卡片（需开启 update_multi）
 type InteractiveV1CardUpdateCardParam struct {
-	OpenIds  []string `json:"open_ids"`
-	Config   interface{}
-	Header   interface{}
-	Elements interface{}
+	OpenIds  []string                   `json:"open_ids,omitempty"`
+	Config   *CardConfig                `json:"config,omitempty"`
+	Header   *CardHeader                `json:"header,omitempty"`
+	CardLink *CardUrl                   `json:"card_link,omitempty"`
+	Elements []CardElement              `json:"elements"`
+	Extra    map[string]json.RawMessage `json:"-"` // 同 Card.Extra，如 i18n_elements、i18n_header
+}
+
+func (p InteractiveV1CardUpdateCardParam) MarshalJSON() ([]byte, error) {
+	type alias InteractiveV1CardUpdateCardParam
+	b, err := json.Marshal(alias(p))
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{"open_ids": true}
+	for k := range cardKnownFields {
+		known[k] = true
+	}
+	return appendCardExtra(b, p.Extra, known)
+}
+
+// NewInteractiveV1CardUpdateParam 使用卡片创建延迟更新参数，openIds 为仅更新指定用户的卡片
+func NewInteractiveV1CardUpdateParam(token string, card *Card, openIds ...string) InteractiveV1CardUpdateParam {
+	return InteractiveV1CardUpdateParam{
+		Token: token,
+		Card: InteractiveV1CardUpdateCardParam{
+			OpenIds:  openIds,
+			Config:   card.Config,
+			Header:   card.Header,
+			CardLink: card.CardLink,
+			Elements: card.Elements,
+			Extra:    card.Extra,
+		},
+	}
 }
 
 type InteractiveV1CardUpdateRes struct {
@@ -62,6 +93,7 @@ type MessageResDataItem struct {
 	MessageId      string                       `json:"message_id"`
 	RootId         string                       `json:"root_id"`
 	ParentId       string                       `json:"parent_id"`
+	ThreadId       string                       `json:"thread_id"` // 话题 ID，话题群或话题中的消息才有
 	MsgType        string                       `json:"msg_type"`
 	CreateTime     string                       `json:"create_time"`
 	UpdateTime     string                       `json:"update_time"`
@@ -0,0 +1,487 @@
+package feishu
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MarkdownToPost 将 Markdown 转换为富文本消息，结果可直接用于 NewSendMessagesParam
+//
+// 支持标题、粗体、斜体、删除线、链接、行内代码、代码块、列表、引用、分割线、
+// 图片（![描述](image_key)）以及 @（<at user_id="ou_xxx">名字</at>）。
+// 文档开头的一级标题作为富文本标题，其余标题转为粗体段落；
+// 无法表示的结构（表格、HTML 等）降级为纯文本
+func MarkdownToPost(lang, markdown string) PostContent {
+	title, content := markdownToPost(markdown)
+	return PostContent{lang: &PostBody{Title: title, Content: content}}
+}
+
+// Markdown 将 Markdown 转换后追加到当前语言，开头的一级标题会覆盖当前标题
+func (b *PostBuilder) Markdown(markdown string) *PostBuilder {
+	if b.current == nil {
+		b.Lang(LangZhCn, "")
+	}
+	title, content := markdownToPost(markdown)
+	if title != "" {
+		b.current.Title = title
+	}
+	b.current.Content = append(b.current.Content, content...)
+	return b
+}
+
+var (
+	mdHeading       = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdThematicBreak = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdListItem      = regexp.MustCompile(`^([-*+]|\d{1,9}[.)])(?:[ \t]+(.*)|$)`)
+	mdTableDivider  = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?$`)
+	mdAt            = regexp.MustCompile(`^<at\s+(?:user_id|id)\s*=\s*"?([^"\s>]+)"?\s*>(.*?)</at>`)
+	mdAutolink      = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+)
+
+// mdPostConverter 按行解析 Markdown 块结构
+type mdPostConverter struct {
+	title      string
+	content    [][]PostElement
+	para       []string // 当前段落的各行
+	paraPrefix string   // 当前段落的前缀，用于列表与引用
+	paraStyle  []string
+	inList     bool // 列表中缩进的行不视为代码块
+}
+
+func markdownToPost(markdown string) (string, [][]PostElement) {
+	c := &mdPostConverter{content: [][]PostElement{}}
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.ReplaceAll(lines[i], "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		isListItem := mdListItem.MatchString(trimmed)
+		if trimmed != "" && indent == 0 && !isListItem {
+			c.inList = false
+		}
+
+		switch {
+		case trimmed == "":
+			c.flush()
+
+		case indent < 4 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			c.flush()
+			fence := trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+			language := strings.TrimSpace(trimmed[len(fence):])
+			if fields := strings.Fields(language); len(fields) > 0 {
+				language = fields[0]
+			}
+			var code []string
+			for i++; i < len(lines); i++ {
+				if t := strings.TrimSpace(lines[i]); strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					break
+				}
+				code = append(code, strings.TrimPrefix(lines[i], strings.Repeat(" ", indent)))
+			}
+			c.add([]PostElement{PostCodeBlock(language, strings.Join(code, "\n"))})
+
+		case indent >= 4 && len(c.para) == 0 && !c.inList:
+			// 缩进代码块，空行不结束代码块
+			code := []string{line[4:]}
+			for i+1 < len(lines) {
+				next := strings.ReplaceAll(lines[i+1], "\t", "    ")
+				if strings.TrimSpace(next) != "" && !strings.HasPrefix(next, "    ") {
+					break
+				}
+				i++
+				if len(next) >= 4 {
+					next = next[4:]
+				} else {
+					next = ""
+				}
+				code = append(code, next)
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			c.add([]PostElement{PostCodeBlock("", strings.Join(code, "\n"))})
+
+		case len(c.para) > 0 && c.paraPrefix == "" && indent < 4 && (strings.Trim(trimmed, "=") == "" || strings.Trim(trimmed, "-") == ""):
+			// Setext 标题
+			text := strings.Join(c.para, " ")
+			c.para = nil
+			c.heading(trimmed[0] == '=', text)
+
+		case mdThematicBreak.MatchString(trimmed):
+			c.flush()
+			c.add([]PostElement{PostHr()})
+
+		case mdHeading.MatchString(trimmed):
+			c.flush()
+			m := mdHeading.FindStringSubmatch(trimmed)
+			c.heading(len(m[1]) == 1, m[2])
+
+		case strings.HasPrefix(trimmed, ">"):
+			c.flush()
+			quote := strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " ")
+			c.paraPrefix = "> "
+			c.paraStyle = []string{PostStyleItalic}
+			c.appendLine(quote)
+
+		case isListItem:
+			c.flush()
+			c.inList = true
+			m := mdListItem.FindStringSubmatch(trimmed)
+			marker := "• "
+			if m[1][0] >= '0' && m[1][0] <= '9' {
+				marker = m[1][:len(m[1])-1] + ". "
+			}
+			c.paraPrefix = strings.Repeat("  ", indent/2) + marker
+			c.appendLine(m[2])
+
+		case strings.HasPrefix(trimmed, "|"):
+			// 表格降级为每行一个段落，单元格以 | 分隔
+			c.flush()
+			if mdTableDivider.MatchString(trimmed) {
+				continue
+			}
+			var cells []string
+			for _, cell := range strings.Split(strings.Trim(trimmed, "|"), "|") {
+				cells = append(cells, strings.TrimSpace(cell))
+			}
+			c.add(c.inline(strings.Join(cells, " | "), nil))
+
+		default:
+			c.appendLine(line)
+		}
+	}
+	c.flush()
+	return c.title, c.content
+}
+
+// appendLine 追加段落行，行尾两个空格或反斜杠表示硬换行
+func (c *mdPostConverter) appendLine(line string) {
+	hardBreak := strings.HasSuffix(line, "  ") || strings.HasSuffix(strings.TrimRight(line, " "), "\\")
+	line = strings.TrimSpace(line)
+	if hardBreak {
+		line = strings.TrimSuffix(line, "\\")
+	}
+	c.para = append(c.para, line)
+	if hardBreak {
+		prefix, style := c.paraPrefix, c.paraStyle
+		c.flush()
+		// 列表项与引用内的硬换行保持缩进与样式
+		switch prefix {
+		case "":
+		case "> ":
+			c.paraPrefix, c.paraStyle = prefix, style
+		default:
+			c.paraPrefix, c.paraStyle = strings.Repeat(" ", len([]rune(prefix))), style
+		}
+	}
+}
+
+// heading 文档开头的一级标题作为富文本标题，其余转为粗体段落
+func (c *mdPostConverter) heading(level1 bool, text string) {
+	if level1 && c.title == "" && len(c.content) == 0 {
+		c.title = mdPlainText(c.inline(text, nil))
+		return
+	}
+	c.add(c.inline(text, []string{PostStyleBold}))
+}
+
+func (c *mdPostConverter) flush() {
+	if len(c.para) > 0 {
+		elements := c.inline(strings.Join(c.para, " "), c.paraStyle)
+		if c.paraPrefix != "" {
+			prefixed := []PostElement{PostText(c.paraPrefix)}
+			for _, e := range elements {
+				if e.Tag == PostTagText {
+					prefixed = mdAppendText(prefixed, e)
+				} else {
+					prefixed = append(prefixed, e)
+				}
+			}
+			elements = prefixed
+		}
+		c.add(elements)
+	}
+	c.para, c.paraPrefix, c.paraStyle = nil, "", nil
+}
+
+// add 追加段落，图片拆分为独立段落
+func (c *mdPostConverter) add(elements []PostElement) {
+	var line []PostElement
+	for _, e := range elements {
+		if e.Tag != PostTagImg {
+			line = append(line, e)
+			continue
+		}
+		if len(line) > 0 {
+			c.content = append(c.content, line)
+			line = nil
+		}
+		c.content = append(c.content, []PostElement{e})
+	}
+	if len(line) > 0 {
+		c.content = append(c.content, line)
+	}
+}
+
+// inline 解析行内元素，相邻且样式相同的文本会合并
+func (c *mdPostConverter) inline(s string, style []string) []PostElement {
+	var elements []PostElement
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			elements = mdAppendText(elements, PostText(buf.String(), style...))
+			buf.Reset()
+		}
+	}
+	push := func(e ...PostElement) {
+		flush()
+		for _, element := range e {
+			if element.Tag == PostTagText {
+				elements = mdAppendText(elements, element)
+			} else {
+				elements = append(elements, element)
+			}
+		}
+	}
+
+	for i := 0; i < len(s); {
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s) && strings.IndexByte(mdPunctuation, s[i+1]) >= 0:
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+
+		case ch == '`':
+			// 富文本没有行内代码样式，保留代码原文
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			fence := s[i : i+n]
+			if end := strings.Index(s[i+n:], fence); end >= 0 {
+				code := s[i+n : i+n+end]
+				if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				buf.WriteString(code)
+				i += n + end + n
+				continue
+			}
+			buf.WriteString(fence)
+			i += n
+			continue
+
+		case ch == '!' && i+1 < len(s) && s[i+1] == '[':
+			if text, dest, n, ok := mdParseLink(s[i+1:]); ok {
+				if strings.Contains(dest, "://") {
+					// 外链图片无法上传，降级为链接
+					if text == "" {
+						text = dest
+					}
+					push(PostLink(text, dest, style...))
+				} else {
+					push(PostImage(dest))
+				}
+				i += 1 + n
+				continue
+			}
+
+		case ch == '[':
+			if text, dest, n, ok := mdParseLink(s[i:]); ok {
+				text = mdPlainText(c.inline(text, nil))
+				if text == "" {
+					text = dest
+				}
+				push(PostLink(text, dest, style...))
+				i += n
+				continue
+			}
+
+		case ch == '<':
+			if m := mdAt.FindStringSubmatch(s[i:]); m != nil {
+				if m[1] == "all" {
+					push(PostAtAll())
+				} else {
+					push(PostAt(m[1], m[2]))
+				}
+				i += len(m[0])
+				continue
+			}
+			if m := mdAutolink.FindStringSubmatch(s[i:]); m != nil {
+				push(PostLink(m[1], m[1], style...))
+				i += len(m[0])
+				continue
+			}
+
+		case ch == '*' || ch == '_' || ch == '~':
+			if inner, delimStyle, n, ok := mdParseEmphasis(s, i); ok {
+				push(c.inline(inner, mdAddStyle(style, delimStyle))...)
+				i += n
+				continue
+			}
+			// 未闭合的分隔符整体作为文本
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], s[i:i+1]))
+			buf.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		buf.WriteByte(ch)
+		i++
+	}
+	flush()
+	return elements
+}
+
+const mdPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// mdParseLink 解析 [text](dest "title")，返回消耗的字节数
+func mdParseLink(s string) (text, dest string, n int, ok bool) {
+	depth := 0
+	end := -1
+	for i := 0; i < len(s) && end < 0; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 || end+1 >= len(s) || s[end+1] != '(' {
+		return "", "", 0, false
+	}
+	depth = 0
+	for i := end + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				dest = strings.TrimSpace(s[end+2 : i])
+				if fields := strings.Fields(dest); len(fields) > 0 {
+					dest = fields[0]
+				}
+				dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+				if dest == "" {
+					return "", "", 0, false
+				}
+				return s[1:end], dest, i + 1, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// mdParseEmphasis 解析 **粗体**、__粗体__、*斜体*、_斜体_、~~删除线~~
+func mdParseEmphasis(s string, i int) (inner, style string, n int, ok bool) {
+	ch := s[i]
+	delim := s[i : i+1]
+	if i+1 < len(s) && s[i+1] == ch {
+		delim = s[i : i+2]
+	}
+	switch {
+	case delim == "~":
+		return "", "", 0, false
+	case delim == "~~":
+		style = PostStyleLineThrough
+	case len(delim) == 2:
+		style = PostStyleBold
+	default:
+		style = PostStyleItalic
+	}
+
+	start := i + len(delim)
+	// 开始分隔符后不能是空白，_ 不能出现在单词中间
+	if start >= len(s) || s[start] == ' ' || (ch == '_' && i > 0 && mdIsWordByte(s[i-1])) {
+		return "", "", 0, false
+	}
+	for j := start + 1; j < len(s); j++ {
+		if s[j] == '\\' {
+			j++
+			continue
+		}
+		if s[j] == '`' {
+			// 跳过行内代码
+			if k := strings.IndexByte(s[j+1:], '`'); k >= 0 {
+				j += k + 1
+			}
+			continue
+		}
+		if !strings.HasPrefix(s[j:], delim) || s[j-1] == ' ' {
+			continue
+		}
+		// 连续的分隔符取最后一组作为结束，如 ***粗斜体***
+		run := len(s[j:]) - len(strings.TrimLeft(s[j:], delim[:1]))
+		end := j + run - len(delim)
+		if len(delim) == 1 && run == 2 {
+			// 斜体中的粗体整体跳过，如 *斜体 **粗体** 斜体*
+			if k := strings.Index(s[j+2:], delim+delim); k >= 0 {
+				j += k + 3
+			} else {
+				j++
+			}
+			continue
+		}
+		if ch == '_' && end+len(delim) < len(s) && mdIsWordByte(s[end+len(delim)]) {
+			continue
+		}
+		if end <= start {
+			continue
+		}
+		return s[start:end], style, end + len(delim) - i, true
+	}
+	return "", "", 0, false
+}
+
+func mdIsWordByte(b byte) bool {
+	return b >= 0x80 || b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func mdAddStyle(style []string, s string) []string {
+	for _, v := range style {
+		if v == s {
+			return style
+		}
+	}
+	return append(append([]string{}, style...), s)
+}
+
+// mdAppendText 追加文本元素，与前一个样式相同的文本合并
+func mdAppendText(elements []PostElement, e PostElement) []PostElement {
+	if n := len(elements); n > 0 && elements[n-1].Tag == PostTagText && mdSameStyle(elements[n-1].Style, e.Style) {
+		elements[n-1].Text += e.Text
+		return elements
+	}
+	return append(elements, e)
+}
+
+func mdSameStyle(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mdPlainText 获取元素的纯文本，用于标题与链接文字
+func mdPlainText(elements []PostElement) string {
+	var b strings.Builder
+	for _, e := range elements {
+		switch e.Tag {
+		case PostTagText, PostTagLink:
+			b.WriteString(e.Text)
+		case PostTagAt:
+			b.WriteString("@" + e.UserName)
+		}
+	}
+	return b.String()
+}
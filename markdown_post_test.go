@@ -0,0 +1,58 @@
+package feishu
+
+import "testing"
+
+func TestMarkdownToPost(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "title and heading",
+			markdown: "# Release v1.2\n\n## Features",
+			want:     `{"title":"Release v1.2","content":[[{"tag":"text","text":"Features","style":["bold"]}]]}`,
+		},
+		{
+			name:     "emphasis",
+			markdown: "a **bold** *it* ~~del~~ ***both*** snake_case_name",
+			want:     `{"title":"","content":[[{"tag":"text","text":"a "},{"tag":"text","text":"bold","style":["bold"]},{"tag":"text","text":" "},{"tag":"text","text":"it","style":["italic"]},{"tag":"text","text":" "},{"tag":"text","text":"del","style":["lineThrough"]},{"tag":"text","text":" "},{"tag":"text","text":"both","style":["bold","italic"]},{"tag":"text","text":" snake_case_name"}]]}`,
+		},
+		{
+			name:     "link, code and mention",
+			markdown: "see [**docs**](https://example.com \"t\") `a*b` <at user_id=\"ou_1\">Tom</at> <at user_id=\"all\"></at>",
+			want:     `{"title":"","content":[[{"tag":"text","text":"see "},{"tag":"a","text":"docs","href":"https://example.com"},{"tag":"text","text":" a*b "},{"tag":"at","user_id":"ou_1","user_name":"Tom"},{"tag":"text","text":" "},{"tag":"at","user_id":"all"}]]}`,
+		},
+		{
+			name:     "code block",
+			markdown: "```go\nfmt.Println(\"*\")\n```\n\n    indented",
+			want:     `{"title":"","content":[[{"tag":"code_block","text":"fmt.Println(\"*\")","language":"go"}],[{"tag":"code_block","text":"indented"}]]}`,
+		},
+		{
+			name:     "lists and quote",
+			markdown: "- one\n  - nested\n\n    - after blank\n1. first\n> quoted",
+			want:     `{"title":"","content":[[{"tag":"text","text":"• one"}],[{"tag":"text","text":"  • nested"}],[{"tag":"text","text":"    • after blank"}],[{"tag":"text","text":"1. first"}],[{"tag":"text","text":"> "},{"tag":"text","text":"quoted","style":["italic"]}]]}`,
+		},
+		{
+			name:     "images, hr and table",
+			markdown: "before ![logo](img_1) after\n\n---\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n![x](https://example.com/x.png)",
+			want:     `{"title":"","content":[[{"tag":"text","text":"before "}],[{"tag":"img","image_key":"img_1"}],[{"tag":"text","text":" after"}],[{"tag":"hr"}],[{"tag":"text","text":"a | b"}],[{"tag":"text","text":"1 | 2"}],[{"tag":"a","text":"x","href":"https://example.com/x.png"}]]}`,
+		},
+		{
+			name:     "soft and hard breaks",
+			markdown: "line one\nline two  \nline three\\\n\\*literal\\* * not emphasis",
+			want:     `{"title":"","content":[[{"tag":"text","text":"line one line two"}],[{"tag":"text","text":"line three"}],[{"tag":"text","text":"*literal* * not emphasis"}]]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalMessageContent(MarkdownToPost(LangZhCn, tt.markdown))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != `{"zh_cn":`+tt.want+`}` {
+				t.Errorf("MarkdownToPost() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
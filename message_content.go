@@ -0,0 +1,303 @@
+package feishu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 消息类型
+const (
+	MsgTypeText        = "text"        // 文本
+	MsgTypePost        = "post"        // 富文本
+	MsgTypeImage       = "image"       // 图片
+	MsgTypeFile        = "file"        // 文件
+	MsgTypeAudio       = "audio"       // 语音
+	MsgTypeMedia       = "media"       // 视频
+	MsgTypeSticker     = "sticker"     // 表情包
+	MsgTypeInteractive = "interactive" // 消息卡片
+	MsgTypeShareChat   = "share_chat"  // 分享群名片
+	MsgTypeShareUser   = "share_user"  // 分享个人名片
+)
+
+// 多语言
+const (
+	LangZhCn = "zh_cn"
+	LangEnUs = "en_us"
+	LangJaJp = "ja_jp"
+)
+
+// MessageContent 消息内容，序列化结果即为 content 字段
+type MessageContent interface {
+	MsgType() string
+}
+
+// MarshalMessageContent 序列化消息内容，不转义 HTML 以保留 <at> 等标签的可读性
+func MarshalMessageContent(content MessageContent) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(content); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// NewSendMessagesParam 使用消息内容创建发送消息参数
+func NewSendMessagesParam(receiveIdType, receiveId string, content MessageContent) (SendMessagesParam, error) {
+	param := SendMessagesParam{
+		ReceiveIdType: receiveIdType,
+		ReceiveId:     receiveId,
+	}
+	err := param.SetContent(content)
+	return param, err
+}
+
+// SetContent 设置消息内容与消息类型
+func (p *SendMessagesParam) SetContent(content MessageContent) error {
+	s, err := MarshalMessageContent(content)
+	if err != nil {
+		return err
+	}
+	p.MsgType = content.MsgType()
+	p.Content = s
+	return nil
+}
+
+// SetContent 设置批量消息内容与消息类型
+//
+// 批量发送接口仅支持 text、image、post、share_chat、interactive，且 content 结构与 im/v1 不同
+func (p *BatchSendMessagesParam) SetContent(content MessageContent) error {
+	c, card, err := legacyContent(content)
+	if err != nil {
+		return err
+	}
+	p.MsgType = content.MsgType()
+	p.Content = c
+	p.Card = card
+	return nil
+}
+
+// legacyContent 转换为批量发送与自定义机器人使用的 content 结构，卡片单独放在 card 字段
+func legacyContent(content MessageContent) (interface{}, *Card, error) {
+	switch c := content.(type) {
+	case *TextContent:
+		return c, nil, nil
+	case *ImageContent:
+		return c, nil, nil
+	case PostContent:
+		return map[string]interface{}{"post": c}, nil, nil
+	case *ShareChatContent:
+		return map[string]string{"share_chat_id": c.ChatId}, nil, nil
+	case *Card:
+		return nil, c, nil
+	}
+	return nil, nil, fmt.Errorf("feishu: msg_type %s is not supported", content.MsgType())
+}
+
+// TextContent 文本消息
+type TextContent struct {
+	Text string `json:"text"`
+}
+
+func (*TextContent) MsgType() string { return MsgTypeText }
+
+// NewTextContent 创建文本消息
+func NewTextContent(text string) *TextContent {
+	return &TextContent{Text: text}
+}
+
+// TextAt 文本消息中 @ 指定用户，userId 为 open_id 或 user_id
+func TextAt(userId, name string) string {
+	return `<at user_id="` + userId + `">` + name + `</at>`
+}
+
+// TextAtAll 文本消息中 @ 所有人
+func TextAtAll() string {
+	return `<at user_id="all">所有人</at>`
+}
+
+// ImageContent 图片消息
+type ImageContent struct {
+	ImageKey string `json:"image_key"`
+}
+
+func (*ImageContent) MsgType() string { return MsgTypeImage }
+
+// FileContent 文件消息
+type FileContent struct {
+	FileKey string `json:"file_key"`
+}
+
+func (*FileContent) MsgType() string { return MsgTypeFile }
+
+// AudioContent 语音消息
+type AudioContent struct {
+	FileKey string `json:"file_key"`
+}
+
+func (*AudioContent) MsgType() string { return MsgTypeAudio }
+
+// MediaContent 视频消息，ImageKey 为视频封面
+type MediaContent struct {
+	FileKey  string `json:"file_key"`
+	ImageKey string `json:"image_key,omitempty"`
+}
+
+func (*MediaContent) MsgType() string { return MsgTypeMedia }
+
+// StickerContent 表情包消息，仅支持转发收到的表情包
+type StickerContent struct {
+	FileKey string `json:"file_key"`
+}
+
+func (*StickerContent) MsgType() string { return MsgTypeSticker }
+
+// ShareChatContent 分享群名片
+type ShareChatContent struct {
+	ChatId string `json:"chat_id"`
+}
+
+func (*ShareChatContent) MsgType() string { return MsgTypeShareChat }
+
+// ShareUserContent 分享个人名片
+type ShareUserContent struct {
+	UserId string `json:"user_id"`
+}
+
+func (*ShareUserContent) MsgType() string { return MsgTypeShareUser }
+
+// 富文本元素标签
+const (
+	PostTagText      = "text"
+	PostTagLink      = "a"
+	PostTagAt        = "at"
+	PostTagImg       = "img"
+	PostTagMedia     = "media"
+	PostTagEmotion   = "emotion"
+	PostTagCodeBlock = "code_block"
+	PostTagHr        = "hr"
+	PostTagMd        = "md"
+)
+
+// 富文本文字样式
+const (
+	PostStyleBold        = "bold"
+	PostStyleItalic      = "italic"
+	PostStyleUnderline   = "underline"
+	PostStyleLineThrough = "lineThrough"
+)
+
+// PostContent 富文本消息，key 为语言
+type PostContent map[string]*PostBody
+
+func (PostContent) MsgType() string { return MsgTypePost }
+
+// PostBody 单个语言的富文本内容，Content 的每个元素为一个段落
+type PostBody struct {
+	Title   string          `json:"title"`
+	Content [][]PostElement `json:"content"`
+}
+
+// PostElement 富文本元素
+type PostElement struct {
+	Tag       string   `json:"tag"`
+	Text      string   `json:"text,omitempty"`
+	UnEscape  bool     `json:"un_escape,omitempty"`
+	Style     []string `json:"style,omitempty"`
+	Href      string   `json:"href,omitempty"`
+	UserId    string   `json:"user_id,omitempty"`
+	UserName  string   `json:"user_name,omitempty"`
+	ImageKey  string   `json:"image_key,omitempty"`
+	FileKey   string   `json:"file_key,omitempty"`
+	EmojiType string   `json:"emoji_type,omitempty"`
+	Language  string   `json:"language,omitempty"`
+}
+
+// PostText 文本
+func PostText(text string, style ...string) PostElement {
+	return PostElement{Tag: PostTagText, Text: text, Style: style}
+}
+
+// PostLink 超链接
+func PostLink(text, href string, style ...string) PostElement {
+	return PostElement{Tag: PostTagLink, Text: text, Href: href, Style: style}
+}
+
+// PostAt @ 用户，userId 为 open_id 或 user_id
+func PostAt(userId, userName string) PostElement {
+	return PostElement{Tag: PostTagAt, UserId: userId, UserName: userName}
+}
+
+// PostAtAll @ 所有人
+func PostAtAll() PostElement {
+	return PostElement{Tag: PostTagAt, UserId: "all"}
+}
+
+// PostImage 图片
+func PostImage(imageKey string) PostElement {
+	return PostElement{Tag: PostTagImg, ImageKey: imageKey}
+}
+
+// PostMedia 视频，imageKey 为封面
+func PostMedia(fileKey, imageKey string) PostElement {
+	return PostElement{Tag: PostTagMedia, FileKey: fileKey, ImageKey: imageKey}
+}
+
+// PostEmotion 表情
+func PostEmotion(emojiType string) PostElement {
+	return PostElement{Tag: PostTagEmotion, EmojiType: emojiType}
+}
+
+// PostCodeBlock 代码块
+func PostCodeBlock(language, code string) PostElement {
+	return PostElement{Tag: PostTagCodeBlock, Language: language, Text: code}
+}
+
+// PostHr 分割线
+func PostHr() PostElement {
+	return PostElement{Tag: PostTagHr}
+}
+
+// PostMarkdown Markdown，需独占一个段落
+func PostMarkdown(text string) PostElement {
+	return PostElement{Tag: PostTagMd, Text: text}
+}
+
+// PostBuilder 富文本消息构造器
+type PostBuilder struct {
+	content PostContent
+	current *PostBody
+}
+
+// NewPostBuilder 创建富文本消息构造器
+func NewPostBuilder() *PostBuilder {
+	return &PostBuilder{content: PostContent{}}
+}
+
+// Lang 切换到指定语言，后续的段落写入该语言
+func (b *PostBuilder) Lang(lang, title string) *PostBuilder {
+	body, ok := b.content[lang]
+	if !ok {
+		body = &PostBody{Content: [][]PostElement{}}
+		b.content[lang] = body
+	}
+	body.Title = title
+	b.current = body
+	return b
+}
+
+// Line 追加一个段落，未调用 Lang 时默认为中文
+func (b *PostBuilder) Line(elements ...PostElement) *PostBuilder {
+	if b.current == nil {
+		b.Lang(LangZhCn, "")
+	}
+	b.current.Content = append(b.current.Content, elements)
+	return b
+}
+
+// Build 获取富文本消息
+func (b *PostBuilder) Build() PostContent {
+	return b.content
+}
@@ -0,0 +1,56 @@
+package feishu
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalMessageContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content MessageContent
+		want    string
+	}{
+		{name: "text", content: NewTextContent("hi " + TextAt("ou_1", "Tom")), want: `{"text":"hi <at user_id=\"ou_1\">Tom</at>"}`},
+		{name: "image", content: &ImageContent{ImageKey: "img_1"}, want: `{"image_key":"img_1"}`},
+		{name: "media", content: &MediaContent{FileKey: "file_1"}, want: `{"file_key":"file_1"}`},
+		{name: "share_chat", content: &ShareChatContent{ChatId: "oc_1"}, want: `{"chat_id":"oc_1"}`},
+		{
+			name: "post",
+			content: NewPostBuilder().
+				Lang(LangZhCn, "标题").
+				Line(PostText("加粗", PostStyleBold), PostLink("链接", "https://example.com"), PostAt("ou_1", "Tom")).
+				Line(PostCodeBlock("go", "fmt.Println()")).
+				Lang(LangEnUs, "Title").
+				Line(PostImage("img_1")).
+				Build(),
+			want: `{"en_us":{"title":"Title","content":[[{"tag":"img","image_key":"img_1"}]]},"zh_cn":{"title":"标题","content":[[{"tag":"text","text":"加粗","style":["bold"]},{"tag":"a","text":"链接","href":"https://example.com"},{"tag":"at","user_id":"ou_1","user_name":"Tom"}],[{"tag":"code_block","text":"fmt.Println()","language":"go"}]]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalMessageContent(tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("MarshalMessageContent() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchSendMessagesParamSetContent(t *testing.T) {
+	var param BatchSendMessagesParam
+	if err := param.SetContent(&ShareChatContent{ChatId: "oc_1"}); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(param.Content)
+	if param.MsgType != MsgTypeShareChat || string(b) != `{"share_chat_id":"oc_1"}` {
+		t.Errorf("SetContent() = %s %s", param.MsgType, b)
+	}
+
+	if err := param.SetContent(&FileContent{FileKey: "f"}); err == nil {
+		t.Error("SetContent() file should fail")
+	}
+}
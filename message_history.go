@@ -0,0 +1,145 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// 消息容器类型
+const (
+	ContainerIdTypeChat   = "chat"   // 会话
+	ContainerIdTypeThread = "thread" // 话题
+)
+
+// 会话历史排序方式
+const (
+	SortByCreateTimeAsc  = "ByCreateTimeAsc"
+	SortByCreateTimeDesc = "ByCreateTimeDesc"
+)
+
+// ListMessagesParam 获取会话历史消息的请求结构体
+type ListMessagesParam struct {
+	ContainerIdType string `json:"container_id_type"` // 容器类型，chat 或 thread，默认 chat
+	ContainerId     string `json:"container_id"`
+	StartTime       string `json:"start_time"` // 起始时间，秒级时间戳，thread 容器不支持
+	EndTime         string `json:"end_time"`   // 结束时间，秒级时间戳，thread 容器不支持
+	SortType        string `json:"sort_type"`
+	PageSize        int64  `json:"page_size"` // 最大 50
+	PageToken       string `json:"page_token"`
+}
+
+// ListMessagesRes 获取会话历史消息的响应结构体
+type ListMessagesRes struct {
+	ResponseCode
+	Data ListMessagesResData `json:"data"`
+}
+
+type ListMessagesResData struct {
+	HasMore   bool                 `json:"has_more"`
+	PageToken string               `json:"page_token"`
+	Items     []MessageResDataItem `json:"items"`
+}
+
+// ListMessages 获取会话历史消息
+func (c *Client) ListMessages(param ListMessagesParam) (*ListMessagesRes, error) {
+	params := url.Values{}
+	if param.ContainerIdType == "" {
+		param.ContainerIdType = ContainerIdTypeChat
+	}
+	params.Add("container_id_type", param.ContainerIdType)
+	params.Add("container_id", param.ContainerId)
+	if param.StartTime != "" {
+		params.Add("start_time", param.StartTime)
+	}
+	if param.EndTime != "" {
+		params.Add("end_time", param.EndTime)
+	}
+	if param.SortType != "" {
+		params.Add("sort_type", param.SortType)
+	}
+	if param.PageSize > 0 {
+		params.Add("page_size", fmt.Sprintf("%v", param.PageSize))
+	}
+	if param.PageToken != "" {
+		params.Add("page_token", param.PageToken)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/messages?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ListMessagesRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// ExportedMessage 导出的单条消息，对应 JSONL 中的一行
+type ExportedMessage struct {
+	MessageId   string                   `json:"message_id"`
+	RootId      string                   `json:"root_id,omitempty"`
+	ParentId    string                   `json:"parent_id,omitempty"`
+	ThreadId    string                   `json:"thread_id,omitempty"`
+	ChatId      string                   `json:"chat_id"`
+	MsgType     string                   `json:"msg_type"`
+	CreateTime  string                   `json:"create_time"`
+	UpdateTime  string                   `json:"update_time,omitempty"`
+	Deleted     bool                     `json:"deleted,omitempty"`
+	Updated     bool                     `json:"updated,omitempty"`
+	Sender      MessageResDataItemSender `json:"sender"`
+	Text        string                   `json:"text"`
+	Attachments []MessageAttachment      `json:"attachments,omitempty"`
+}
+
+// MessageAttachment 消息中的附件，可通过 GetMessageResource 下载
+type MessageAttachment struct {
+	Type string `json:"type"` // image 或 file
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
+}
+
+// ExportChatHistory 将会话的全部历史消息按时间顺序以 JSONL 格式写入 w
+//
+// param.PageToken 与 param.SortType 会被忽略，返回导出的消息数量
+func (c *Client) ExportChatHistory(ctx context.Context, w io.Writer, param ListMessagesParam) (count int, err error) {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	err = c.listAllMessages(ctx, param, 0, func(item MessageResDataItem) error {
+		if err := encoder.Encode(ExportMessage(item)); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return
+}
+
+// ExportMessage 转换为导出格式，消息体渲染为纯文本
+func ExportMessage(item MessageResDataItem) ExportedMessage {
+	rendered := item.Render()
+	return ExportedMessage{
+		MessageId:   item.MessageId,
+		RootId:      item.RootId,
+		ParentId:    item.ParentId,
+		ThreadId:    item.ThreadId,
+		ChatId:      item.ChatId,
+		MsgType:     item.MsgType,
+		CreateTime:  item.CreateTime,
+		UpdateTime:  item.UpdateTime,
+		Deleted:     item.Deleted,
+		Updated:     item.Updated,
+		Sender:      item.Sender,
+		Text:        rendered.Text,
+		Attachments: rendered.Attachments,
+	}
+}
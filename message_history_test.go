@@ -0,0 +1,63 @@
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExportChatHistory(t *testing.T) {
+	var pages int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		q := r.URL.Query()
+		if r.URL.Path != "/open-apis/im/v1/messages" || q.Get("container_id") != "oc_1" ||
+			q.Get("start_time") != "1600000000" || q.Get("end_time") != "1700000000" || q.Get("sort_type") != SortByCreateTimeAsc {
+			t.Errorf("url = %s", r.URL)
+		}
+		switch q.Get("page_token") {
+		case "":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"has_more":true,"page_token":"p2","items":[
+				{"message_id":"om_1","chat_id":"oc_1","msg_type":"text","create_time":"1","body":{"content":"{\"text\":\"hello\"}"}},
+				{"message_id":"om_2","chat_id":"oc_1","msg_type":"image","create_time":"2","body":{"content":"{\"image_key\":\"img_1\"}"}}]}}`))
+		case "p2":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"has_more":false,"items":[
+				{"message_id":"om_3","chat_id":"oc_1","msg_type":"text","create_time":"3","body":{"content":"{\"text\":\"<bye>\"}"}}]}}`))
+		default:
+			t.Errorf("page_token = %s", q.Get("page_token"))
+		}
+	}))
+
+	var buf bytes.Buffer
+	count, err := client.ExportChatHistory(context.Background(), &buf, ListMessagesParam{
+		ContainerId: "oc_1",
+		StartTime:   "1600000000",
+		EndTime:     "1700000000",
+		PageToken:   "ignored",
+	})
+	if err != nil || count != 3 || pages != 2 {
+		t.Fatalf("ExportChatHistory() = %d, %v, pages = %d", count, err, pages)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[2], `"text":"<bye>"`) {
+		t.Fatalf("output = %s", buf.String())
+	}
+	var messages []ExportedMessage
+	for _, line := range lines {
+		var message ExportedMessage
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, message)
+	}
+	if messages[0].MessageId != "om_1" || messages[0].Text != "hello" || messages[2].MessageId != "om_3" {
+		t.Errorf("messages = %+v", messages)
+	}
+	if len(messages[1].Attachments) != 1 || messages[1].Attachments[0].Key != "img_1" {
+		t.Errorf("attachments = %+v", messages[1].Attachments)
+	}
+}
@@ -0,0 +1,381 @@
+package feishu
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 仅出现在接收消息中的消息类型，合并转发的子消息需通过 Messages 获取
+const (
+	MsgTypeMergeForward = "merge_forward"
+	MsgTypeSystem       = "system"
+	MsgTypeLocation     = "location"
+)
+
+// RenderedMessage 消息体渲染结果
+type RenderedMessage struct {
+	Text        string              // 纯文本，用于搜索与语义分析
+	Markdown    string              // Markdown
+	Attachments []MessageAttachment // 图片、文件等附件
+}
+
+// MessageMentions @ 占位符到用户名的映射，如 @_user_1 => 张三
+type MessageMentions map[string]string
+
+// Render 渲染消息体
+func (item MessageResDataItem) Render() *RenderedMessage {
+	mentions := make(MessageMentions, len(item.Mentions))
+	for _, m := range item.Mentions {
+		mentions[m.Key] = m.Name
+	}
+	return RenderMessage(item.MsgType, item.Body.Content, mentions)
+}
+
+// Render 渲染接收到的消息体
+func (m MessageReceiveEventMessage) Render() *RenderedMessage {
+	mentions := make(MessageMentions, len(m.Mentions))
+	for _, mention := range m.Mentions {
+		mentions[mention.Key] = mention.Name
+	}
+	return RenderMessage(m.MessageType, m.Content, mentions)
+}
+
+// RenderMessage 将任意类型的消息体渲染为纯文本与 Markdown，@ 占位符替换为用户名
+//
+// 无法识别的消息类型渲染为 [msg_type]，解析失败时原样返回 content
+func RenderMessage(msgType, content string, mentions MessageMentions) *RenderedMessage {
+	r := &messageRenderer{mentions: mentions.replacer()}
+	if err := r.render(msgType, content); err != nil {
+		return &RenderedMessage{Text: content, Markdown: content}
+	}
+	return &RenderedMessage{
+		Text:        strings.Join(r.text, "\n"),
+		Markdown:    strings.Join(r.markdown, "\n"),
+		Attachments: r.attachments,
+	}
+}
+
+// RenderMergeForward 渲染合并转发消息，items 为 Messages 返回的消息列表，子消息以引用形式列出
+func RenderMergeForward(items []MessageResDataItem) *RenderedMessage {
+	rendered := &RenderedMessage{}
+	if len(items) == 0 {
+		return rendered
+	}
+	parent := items[0].Render()
+	text, markdown := []string{parent.Text}, []string{parent.Markdown}
+	rendered.Attachments = parent.Attachments
+	for _, item := range items[1:] {
+		if item.UpperMessageId != items[0].MessageId {
+			continue
+		}
+		child := item.Render()
+		for _, line := range strings.Split(child.Text, "\n") {
+			text = append(text, "> "+line)
+		}
+		for _, line := range strings.Split(child.Markdown, "\n") {
+			markdown = append(markdown, "> "+line)
+		}
+		rendered.Attachments = append(rendered.Attachments, child.Attachments...)
+	}
+	rendered.Text = strings.Join(text, "\n")
+	rendered.Markdown = strings.Join(markdown, "\n")
+	return rendered
+}
+
+// replacer 按 key 长度降序替换，避免 @_user_1 覆盖 @_user_10
+func (m MessageMentions) replacer() *strings.Replacer {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	oldnew := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		oldnew = append(oldnew, k, "@"+m[k])
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// messageRenderer 逐行输出纯文本与 Markdown
+type messageRenderer struct {
+	mentions    *strings.Replacer
+	text        []string
+	markdown    []string
+	attachments []MessageAttachment
+}
+
+func (r *messageRenderer) line(text, markdown string) {
+	r.text = append(r.text, text)
+	r.markdown = append(r.markdown, markdown)
+}
+
+func (r *messageRenderer) render(msgType, content string) error {
+	var body struct {
+		Text       string          `json:"text"`
+		Title      string          `json:"title"`
+		ImageKey   string          `json:"image_key"`
+		FileKey    string          `json:"file_key"`
+		FileName   string          `json:"file_name"`
+		ChatId     string          `json:"chat_id"`
+		UserId     string          `json:"user_id"`
+		Name       string          `json:"name"`
+		Template   string          `json:"template"`
+		Content    json.RawMessage `json:"content"`
+		Elements   json.RawMessage `json:"elements"`
+		Header     json.RawMessage `json:"header"`
+		FromUser   []string        `json:"from_user"`
+		ToChatters []string        `json:"to_chatters"`
+	}
+	if err := json.Unmarshal([]byte(content), &body); err != nil {
+		return err
+	}
+
+	switch msgType {
+	case MsgTypeText:
+		text := r.mentions.Replace(body.Text)
+		r.line(text, mdEscape(text))
+	case MsgTypePost:
+		r.renderPost(content)
+	case MsgTypeInteractive:
+		r.renderCard(body.Title, body.Header, body.Elements)
+	case MsgTypeImage:
+		r.line("[图片]", "![图片]("+body.ImageKey+")")
+		r.attachments = append(r.attachments, MessageAttachment{Type: "image", Key: body.ImageKey})
+	case MsgTypeFile, MsgTypeAudio, MsgTypeMedia, MsgTypeSticker:
+		label := map[string]string{MsgTypeFile: "文件", MsgTypeAudio: "语音", MsgTypeMedia: "视频", MsgTypeSticker: "表情包"}[msgType]
+		text := "[" + label + "]"
+		if body.FileName != "" {
+			text += " " + body.FileName
+		}
+		r.line(text, mdEscape(text))
+		r.attachments = append(r.attachments, MessageAttachment{Type: "file", Key: body.FileKey, Name: body.FileName})
+	case MsgTypeShareChat:
+		r.line("[群名片] "+body.ChatId, "[群名片] "+mdEscape(body.ChatId))
+	case MsgTypeShareUser:
+		r.line("[个人名片] "+body.UserId, "[个人名片] "+mdEscape(body.UserId))
+	case MsgTypeMergeForward:
+		r.line("[合并转发]", "[合并转发]")
+	case MsgTypeLocation:
+		r.line("[位置] "+body.Name, "[位置] "+mdEscape(body.Name))
+	case MsgTypeSystem:
+		// 系统消息如 {"template":"{from_user} 邀请 {to_chatters} 加入群聊","from_user":[...],"to_chatters":[...]}
+		text := strings.NewReplacer(
+			"{from_user}", strings.Join(body.FromUser, "、"),
+			"{to_chatters}", strings.Join(body.ToChatters, "、"),
+		).Replace(body.Template)
+		r.line(text, mdEscape(text))
+	default:
+		r.line("["+msgType+"]", "["+msgType+"]")
+	}
+	return nil
+}
+
+// renderPost 渲染富文本，兼容 {"title","content"} 与按语言嵌套的两种格式
+func (r *messageRenderer) renderPost(content string) {
+	var body PostBody
+	if err := json.Unmarshal([]byte(content), &body); err != nil || body.Content == nil {
+		var langs PostContent
+		_ = json.Unmarshal([]byte(content), &langs)
+		for _, lang := range []string{LangZhCn, LangEnUs, LangJaJp} {
+			if b, ok := langs[lang]; ok && b != nil {
+				body = *b
+				break
+			}
+		}
+		if body.Content == nil {
+			for _, b := range langs {
+				if b != nil {
+					body = *b
+					break
+				}
+			}
+		}
+	}
+
+	if body.Title != "" {
+		title := r.mentions.Replace(body.Title)
+		r.line(title, "# "+mdEscape(title))
+	}
+	r.renderElements(body.Content)
+}
+
+// renderElements 渲染富文本与卡片中的段落
+func (r *messageRenderer) renderElements(paragraphs [][]PostElement) {
+	for _, paragraph := range paragraphs {
+		var text, markdown strings.Builder
+		for _, e := range paragraph {
+			switch e.Tag {
+			case PostTagText:
+				s := r.mentions.Replace(e.Text)
+				text.WriteString(s)
+				markdown.WriteString(mdStyle(mdEscape(s), e.Style))
+			case PostTagLink:
+				s := r.mentions.Replace(e.Text)
+				text.WriteString(s)
+				markdown.WriteString(mdStyle("["+mdEscape(s)+"]("+e.Href+")", e.Style))
+			case PostTagAt:
+				name := e.UserName
+				switch {
+				case name != "":
+				case e.UserId == "all":
+					name = "所有人"
+				default:
+					name = strings.TrimPrefix(r.mentions.Replace(e.UserId), "@")
+				}
+				text.WriteString("@" + name)
+				markdown.WriteString("@" + mdEscape(name))
+			case PostTagImg:
+				text.WriteString("[图片]")
+				markdown.WriteString("![图片](" + e.ImageKey + ")")
+				r.attachments = append(r.attachments, MessageAttachment{Type: "image", Key: e.ImageKey})
+			case PostTagMedia:
+				text.WriteString("[视频]")
+				markdown.WriteString("[视频]")
+				r.attachments = append(r.attachments, MessageAttachment{Type: "file", Key: e.FileKey})
+			case PostTagEmotion:
+				text.WriteString("[" + e.EmojiType + "]")
+				markdown.WriteString(":" + e.EmojiType + ":")
+			case PostTagCodeBlock:
+				text.WriteString(e.Text)
+				markdown.WriteString("```" + e.Language + "\n" + e.Text + "\n```")
+			case PostTagHr:
+				text.WriteString("----")
+				markdown.WriteString("---")
+			case PostTagMd:
+				s := r.mentions.Replace(e.Text)
+				text.WriteString(s)
+				markdown.WriteString(s)
+			default:
+				s := r.mentions.Replace(e.Text)
+				text.WriteString(s)
+				markdown.WriteString(mdEscape(s))
+			}
+		}
+		r.line(text.String(), markdown.String())
+	}
+}
+
+// renderCard 渲染卡片，兼容获取消息时返回的 {"title","elements":[[...]]} 与原始卡片 JSON
+func (r *messageRenderer) renderCard(title string, header, elements json.RawMessage) {
+	var paragraphs [][]PostElement
+	if err := json.Unmarshal(elements, &paragraphs); err == nil {
+		if title != "" {
+			title = r.mentions.Replace(title)
+			r.line(title, "# "+mdEscape(title))
+		}
+		r.renderElements(paragraphs)
+		return
+	}
+
+	var h struct {
+		Title cardRenderNode `json:"title"`
+	}
+	_ = json.Unmarshal(header, &h)
+	if title := r.mentions.Replace(h.Title.Content); title != "" {
+		r.line(title, "# "+mdEscape(title))
+	}
+	var nodes []cardRenderNode
+	_ = json.Unmarshal(elements, &nodes)
+	for _, node := range nodes {
+		r.renderCardNode(node)
+	}
+}
+
+// cardRenderNode 原始卡片元素中用于渲染的字段
+type cardRenderNode struct {
+	Tag      string           `json:"tag"`
+	Content  string           `json:"content"`
+	Text     *cardRenderNode  `json:"text"`
+	Fields   []cardRenderNode `json:"fields"`
+	Elements []cardRenderNode `json:"elements"`
+	Columns  []cardRenderNode `json:"columns"`
+	Actions  []cardRenderNode `json:"actions"`
+	ImgKey   string           `json:"img_key"`
+	Alt      *cardRenderNode  `json:"alt"`
+	Url      string           `json:"url"`
+}
+
+func (r *messageRenderer) renderCardNode(node cardRenderNode) {
+	switch node.Tag {
+	case CardTagHr:
+		r.line("----", "---")
+	case CardTagImg:
+		r.line("[图片]", "![图片]("+node.ImgKey+")")
+		r.attachments = append(r.attachments, MessageAttachment{Type: "image", Key: node.ImgKey})
+	case CardTagMarkdown, "lark_md":
+		s := r.mentions.Replace(node.Content)
+		r.line(mdStrip(s), s)
+	case "plain_text":
+		s := r.mentions.Replace(node.Content)
+		r.line(s, mdEscape(s))
+	case "button":
+		if node.Text != nil {
+			s := r.mentions.Replace(node.Text.Content)
+			r.line("["+s+"]", "["+mdEscape(s)+"]")
+		}
+	default:
+		if node.Text != nil {
+			r.renderCardNode(*node.Text)
+		}
+		for _, children := range [][]cardRenderNode{node.Fields, node.Elements, node.Columns, node.Actions} {
+			for _, child := range children {
+				r.renderCardNode(child)
+			}
+		}
+	}
+}
+
+// mdEscape 转义 Markdown 特殊字符
+func mdEscape(s string) string {
+	return mdEscaper.Replace(s)
+}
+
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `~`, `\~`,
+	`[`, `\[`, `]`, `\]`, `<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`,
+)
+
+var (
+	mdAtPattern       = regexp.MustCompile(`<at\s+(?:id|user_id|open_id|email)\s*=\s*"?([^"\s>]*)"?\s*>([^<]*)</at>`)
+	mdLinkPattern     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	mdTagPattern      = regexp.MustCompile(`</?[a-zA-Z_]+(?:\s[^>]*)?>`)
+	mdEmphasisPattern = regexp.MustCompile("\\*\\*|~~|__|[*`]")
+)
+
+// mdStrip 去除卡片 Markdown 的语法，用于纯文本输出：
+// @ 标签转为 @名称，链接保留文字，删除强调、代码与 font 等标签
+func mdStrip(s string) string {
+	s = mdAtPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdAtPattern.FindStringSubmatch(m)
+		switch {
+		case sub[2] != "":
+			return "@" + sub[2]
+		case sub[1] == "all":
+			return "@所有人"
+		}
+		return "@" + sub[1]
+	})
+	s = mdLinkPattern.ReplaceAllString(s, "$1")
+	s = mdTagPattern.ReplaceAllString(s, "")
+	return mdEmphasisPattern.ReplaceAllString(s, "")
+}
+
+// mdStyle 为 Markdown 文本添加富文本样式
+func mdStyle(s string, style []string) string {
+	if s == "" {
+		return s
+	}
+	for _, v := range style {
+		switch v {
+		case PostStyleBold:
+			s = "**" + s + "**"
+		case PostStyleItalic:
+			s = "*" + s + "*"
+		case PostStyleLineThrough:
+			s = "~~" + s + "~~"
+		}
+	}
+	return s
+}
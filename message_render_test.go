@@ -0,0 +1,90 @@
+package feishu
+
+import "testing"
+
+func TestRenderMessage(t *testing.T) {
+	mentions := MessageMentions{"@_user_1": "Tom", "@_user_10": "Jerry"}
+	tests := []struct {
+		name        string
+		msgType     string
+		content     string
+		text        string
+		markdown    string
+		attachments int
+	}{
+		{
+			name:     "text with mentions",
+			msgType:  MsgTypeText,
+			content:  `{"text":"@_user_1 @_user_10 fix *it*"}`,
+			text:     "@Tom @Jerry fix *it*",
+			markdown: `@Tom @Jerry fix \*it\*`,
+		},
+		{
+			name:        "post",
+			msgType:     MsgTypePost,
+			content:     `{"title":"日报","content":[[{"tag":"text","text":"done","style":["bold"]},{"tag":"a","text":"PR","href":"https://example.com"},{"tag":"at","user_id":"@_user_1","user_name":""}],[{"tag":"img","image_key":"img_1"}]]}`,
+			text:        "日报\ndonePR@Tom\n[图片]",
+			markdown:    "# 日报\n**done**[PR](https://example.com)@Tom\n![图片](img_1)",
+			attachments: 1,
+		},
+		{
+			name:     "post by language",
+			msgType:  MsgTypePost,
+			content:  `{"en_us":{"title":"","content":[[{"tag":"code_block","language":"go","text":"x := 1"}]]}}`,
+			text:     "x := 1",
+			markdown: "```go\nx := 1\n```",
+		},
+		{
+			name:     "card from message api",
+			msgType:  MsgTypeInteractive,
+			content:  `{"title":"告警","elements":[[{"tag":"text","text":"CPU 90%"}]]}`,
+			text:     "告警\nCPU 90%",
+			markdown: "# 告警\nCPU 90%",
+		},
+		{
+			name:        "raw card",
+			msgType:     MsgTypeInteractive,
+			content:     `{"header":{"title":{"tag":"plain_text","content":"告警"}},"elements":[{"tag":"div","text":{"tag":"lark_md","content":"**CPU** 90%"}},{"tag":"hr"},{"tag":"img","img_key":"img_2"},{"tag":"action","actions":[{"tag":"button","text":{"tag":"plain_text","content":"处理"}}]}]}`,
+			text:        "告警\nCPU 90%\n----\n[图片]\n[处理]",
+			markdown:    "# 告警\n**CPU** 90%\n---\n![图片](img_2)\n[处理]",
+			attachments: 1,
+		},
+		{
+			name:     "card markdown syntax",
+			msgType:  MsgTypeInteractive,
+			content:  `{"elements":[{"tag":"markdown","content":"<at id=ou_1>张三</at> <at id=all></at> ~~旧~~ *新* [详情](https://example.com) <font color='red'>` + "`err`" + `</font>"}]}`,
+			text:     "@张三 @所有人 旧 新 详情 err",
+			markdown: "<at id=ou_1>张三</at> <at id=all></at> ~~旧~~ *新* [详情](https://example.com) <font color='red'>`err`</font>",
+		},
+		{
+			name:        "file",
+			msgType:     MsgTypeFile,
+			content:     `{"file_key":"file_1","file_name":"a.pdf"}`,
+			text:        "[文件] a.pdf",
+			markdown:    `\[文件\] a.pdf`,
+			attachments: 1,
+		},
+		{
+			name:     "merge forward",
+			msgType:  MsgTypeMergeForward,
+			content:  `{"content":"Merged and Forwarded Message"}`,
+			text:     "[合并转发]",
+			markdown: "[合并转发]",
+		},
+		{
+			name:     "invalid json",
+			msgType:  MsgTypeText,
+			content:  `hello`,
+			text:     "hello",
+			markdown: "hello",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderMessage(tt.msgType, tt.content, mentions)
+			if got.Text != tt.text || got.Markdown != tt.markdown || len(got.Attachments) != tt.attachments {
+				t.Errorf("RenderMessage() = %q, %q, %v", got.Text, got.Markdown, got.Attachments)
+			}
+		})
+	}
+}
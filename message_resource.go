@@ -0,0 +1,253 @@
+package feishu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 上传大小限制
+const (
+	UploadImageMaxSize = 10 << 20 // 图片最大 10MB
+	UploadFileMaxSize  = 30 << 20 // 文件最大 30MB，各文件类型相同
+)
+
+// 图片类型
+const (
+	ImageTypeMessage = "message" // 用于发送消息
+	ImageTypeAvatar  = "avatar"  // 用于设置头像
+)
+
+// 文件类型
+const (
+	FileTypeOpus   = "opus"   // 语音，需转换为 opus 格式
+	FileTypeMp4    = "mp4"    // 视频
+	FileTypePdf    = "pdf"    // PDF 文档
+	FileTypeDoc    = "doc"    // Word 文档
+	FileTypeXls    = "xls"    // Excel 表格
+	FileTypePpt    = "ppt"    // PowerPoint 演示文稿
+	FileTypeStream = "stream" // 其他类型
+)
+
+var (
+	ErrUploadEmpty    = errors.New("feishu: upload content is empty")
+	ErrUploadTooLarge = errors.New("feishu: upload content exceeds size limit")
+)
+
+// UploadImageParam 上传图片的请求结构体
+type UploadImageParam struct {
+	ImageType string
+	Image     io.Reader
+}
+
+// UploadImageRes 上传图片的响应结构体
+type UploadImageRes struct {
+	ResponseCode
+	Data struct {
+		ImageKey string `json:"image_key"`
+	} `json:"data"`
+}
+
+// UploadImage 上传图片
+func (c *Client) UploadImage(param UploadImageParam) (*UploadImageRes, error) {
+	if param.ImageType == "" {
+		param.ImageType = ImageTypeMessage
+	}
+	// 先获取 token，避免请求未发出时写入 multipart 的 goroutine 泄漏
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	body, contentType, err := multipartBody(map[string]string{
+		"image_type": param.ImageType,
+	}, "image", "image", param.Image, UploadImageMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/images", body)
+	request.Header.Set("Content-Type", contentType)
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data UploadImageRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// UploadFileParam 上传文件的请求结构体
+type UploadFileParam struct {
+	FileType string
+	FileName string
+	Duration int64 // 音视频时长，单位毫秒
+	File     io.Reader
+}
+
+// UploadFileRes 上传文件的响应结构体
+type UploadFileRes struct {
+	ResponseCode
+	Data struct {
+		FileKey string `json:"file_key"`
+	} `json:"data"`
+}
+
+// UploadFile 上传文件
+func (c *Client) UploadFile(param UploadFileParam) (*UploadFileRes, error) {
+	if param.FileType == "" {
+		param.FileType = FileTypeStream
+	}
+	fields := map[string]string{
+		"file_type": param.FileType,
+		"file_name": param.FileName,
+	}
+	if param.Duration > 0 {
+		fields["duration"] = strconv.FormatInt(param.Duration, 10)
+	}
+	// 先获取 token，避免请求未发出时写入 multipart 的 goroutine 泄漏
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	body, contentType, err := multipartBody(fields, "file", param.FileName, param.File, UploadFileMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/files", body)
+	request.Header.Set("Content-Type", contentType)
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data UploadFileRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// GetMessageResourceParam 获取消息中的资源文件的请求结构体
+type GetMessageResourceParam struct {
+	MessageId string
+	FileKey   string
+	Type      string // image 或 file，语音、视频、文件均为 file
+}
+
+// GetMessageResourceRes 获取消息中的资源文件的响应结构体
+type GetMessageResourceRes struct {
+	ResponseCode
+	FileName string
+	Size     int64
+}
+
+// GetMessageResource 下载消息中的资源文件并写入 w
+func (c *Client) GetMessageResource(param GetMessageResourceParam, w io.Writer) (*GetMessageResourceRes, error) {
+	params := url.Values{}
+	params.Add("type", param.Type)
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/resources/"+param.FileKey+"?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// 出错时返回 JSON
+	if response.StatusCode != http.StatusOK || strings.HasPrefix(response.Header.Get("Content-Type"), contentTypeApplicationJson) {
+		resp, _ := ioutil.ReadAll(response.Body)
+		var data GetMessageResourceRes
+		if err = json.Unmarshal(resp, &data); err != nil || response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("response.Status %s, response.Body %s", response.Status, resp)
+		}
+		return &data, nil
+	}
+
+	var data GetMessageResourceRes
+	if _, params, err := mime.ParseMediaType(response.Header.Get("Content-Disposition")); err == nil {
+		data.FileName = params["filename"]
+	}
+	data.Size, err = io.Copy(w, response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// multipartBody 以流的方式构造 multipart 请求体，能获取长度的 reader 会预先校验大小
+func multipartBody(fields map[string]string, fileField, fileName string, r io.Reader, maxSize int64) (io.Reader, string, error) {
+	if r == nil {
+		return nil, "", ErrUploadEmpty
+	}
+	if size, ok := readerSize(r); ok {
+		if size == 0 {
+			return nil, "", ErrUploadEmpty
+		}
+		if size > maxSize {
+			return nil, "", fmt.Errorf("%w: %d > %d", ErrUploadTooLarge, size, maxSize)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		for k, v := range fields {
+			if err := writer.WriteField(k, v); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		part, err := writer.CreateFormFile(fileField, fileName)
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+		n, err := io.Copy(part, io.LimitReader(r, maxSize+1))
+		if err == nil && n > maxSize {
+			err = fmt.Errorf("%w: > %d", ErrUploadTooLarge, maxSize)
+		}
+		if err == nil && n == 0 {
+			err = ErrUploadEmpty
+		}
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+		_ = pw.CloseWithError(writer.Close())
+	}()
+
+	return pr, writer.FormDataContentType(), nil
+}
+
+// readerSize 获取 reader 剩余长度
+func readerSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len()), true
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return info.Size() - offset, true
+	}
+	return 0, false
+}
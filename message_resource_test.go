@@ -0,0 +1,78 @@
+package feishu
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego/sync"
+)
+
+// newTestClient 创建请求本地服务的客户端，token 预先写入缓存
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	oldServerUrl := ServerUrl
+	ServerUrl = server.URL
+	t.Cleanup(func() {
+		ServerUrl = oldServerUrl
+		server.Close()
+	})
+
+	m := &DefaultAccessTokenManager{Id: "test", Cache: sync.New()}
+	_ = m.Cache.Save(m.getCacheKey(), "test-token", time.Hour)
+	return &Client{TokenManager: m, HttpClient: http.DefaultClient}
+}
+
+func TestUploadFile(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+		if r.FormValue("file_type") != FileTypePdf || header.Filename != "a.pdf" || string(content) != "%PDF" {
+			t.Errorf("form = %v %s %s", r.MultipartForm.Value, header.Filename, content)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"file_key":"file_1"}}`))
+	}))
+
+	res, err := client.UploadFile(UploadFileParam{FileType: FileTypePdf, FileName: "a.pdf", File: strings.NewReader("%PDF")})
+	if err != nil || res.Data.FileKey != "file_1" {
+		t.Fatalf("UploadFile() = %+v, %v", res, err)
+	}
+
+	_, err = client.UploadImage(UploadImageParam{Image: bytes.NewReader(make([]byte, UploadImageMaxSize+1))})
+	if !errors.Is(err, ErrUploadTooLarge) {
+		t.Errorf("UploadImage() error = %v", err)
+	}
+	_, err = client.UploadFile(UploadFileParam{FileType: FileTypeMp4, FileName: "a.mp4", File: bytes.NewReader(make([]byte, UploadFileMaxSize+1))})
+	if !errors.Is(err, ErrUploadTooLarge) {
+		t.Errorf("UploadFile() error = %v", err)
+	}
+}
+
+func TestGetMessageResource(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/im/v1/messages/om_1/resources/file_1" || r.URL.Query().Get("type") != "file" {
+			t.Errorf("url = %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="a.txt"`)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	var buf bytes.Buffer
+	res, err := client.GetMessageResource(GetMessageResourceParam{MessageId: "om_1", FileKey: "file_1", Type: "file"}, &buf)
+	if err != nil || res.FileName != "a.txt" || res.Size != 5 || buf.String() != "hello" {
+		t.Errorf("GetMessageResource() = %+v, %v, %s", res, err, buf.String())
+	}
+}
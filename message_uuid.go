@@ -0,0 +1,126 @@
+package feishu
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/faabiosr/cachego"
+	"github.com/fengid/feishu/util"
+)
+
+// MessageUuidWindow 平台按 uuid 去重的时间窗口
+const MessageUuidWindow = time.Hour
+
+// MessageUuid 由业务键生成消息幂等键，key 为空时生成随机值
+//
+// 超过 50 字符的键使用 sha1 摘要
+func MessageUuid(key string) string {
+	if key == "" {
+		return util.GetRandString(32)
+	}
+	if len(key) <= 50 {
+		return key
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IdempotentSender 幂等发送消息
+//
+// 同一个键在重试时使用相同的 uuid，由平台保证不会重复发送；
+// 发送成功的结果在本地缓存 Window 时长，重复的键直接返回原结果而不再请求接口
+type IdempotentSender struct {
+	Client     *Client
+	Cache      cachego.Cache
+	Window     time.Duration                   // 结果缓存时长，默认 MessageUuidWindow
+	Prefix     string                          // 缓存键前缀
+	MaxRetries int                             // 频率超限或网络错误时的最大重试次数，默认 3 次
+	Backoff    func(attempt int) time.Duration // 第 attempt 次重试前的等待时间，默认指数退避
+}
+
+// NewIdempotentSender 创建幂等发送器
+func NewIdempotentSender(client *Client, cache cachego.Cache) *IdempotentSender {
+	return &IdempotentSender{
+		Client:     client,
+		Cache:      cache,
+		Window:     MessageUuidWindow,
+		Prefix:     "feishu:message_uuid:",
+		MaxRetries: 3,
+		Backoff:    defaultBackoff,
+	}
+}
+
+// Send 发送消息，key 为空时使用 param.Uuid，两者都为空时生成随机 uuid
+func (s *IdempotentSender) Send(ctx context.Context, key string, param SendMessagesParam) (*SendMessagesRes, error) {
+	param.Uuid = s.uuid(key, param.Uuid)
+	return s.do(ctx, "send:"+param.Uuid, func() (*SendMessagesRes, error) {
+		return s.Client.SendMessages(param)
+	})
+}
+
+// Reply 回复消息，key 为空时使用 param.Uuid，两者都为空时生成随机 uuid
+func (s *IdempotentSender) Reply(ctx context.Context, key string, param ReplyMessagesParam) (*SendMessagesRes, error) {
+	param.Uuid = s.uuid(key, param.Uuid)
+	return s.do(ctx, "reply:"+param.Uuid, func() (*SendMessagesRes, error) {
+		return s.Client.ReplyMessages(param)
+	})
+}
+
+func (s *IdempotentSender) uuid(key, uuid string) string {
+	if key == "" {
+		key = uuid
+	}
+	return MessageUuid(key)
+}
+
+func (s *IdempotentSender) do(ctx context.Context, cacheKey string, send func() (*SendMessagesRes, error)) (*SendMessagesRes, error) {
+	cacheKey = s.Prefix + cacheKey
+	if s.Cache != nil && s.Cache.Contains(cacheKey) {
+		if value, err := s.Cache.Fetch(cacheKey); err == nil {
+			var res SendMessagesRes
+			if err = json.Unmarshal([]byte(value), &res); err == nil {
+				return &res, nil
+			}
+		}
+	}
+
+	backoff := s.Backoff
+	if backoff == nil {
+		backoff = defaultBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		res, err := send()
+		retryable := err != nil || res.Code == CodeFrequencyLimit || res.Code == CodeMessageFrequencyLimit
+		if !retryable || attempt >= s.MaxRetries {
+			if err == nil && res.Code == 0 && s.Cache != nil {
+				s.save(cacheKey, res)
+			}
+			return res, err
+		}
+		if Logger != nil {
+			Logger.Printf("send message %s attempt %d error %v", cacheKey, attempt+1, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff(attempt)):
+		}
+	}
+}
+
+func (s *IdempotentSender) save(cacheKey string, res *SendMessagesRes) {
+	window := s.Window
+	if window <= 0 {
+		window = MessageUuidWindow
+	}
+	value, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+	if err = s.Cache.Save(cacheKey, string(value), window); err != nil && Logger != nil {
+		Logger.Printf("cache message %s error %s", cacheKey, err)
+	}
+}
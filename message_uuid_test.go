@@ -0,0 +1,60 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego/sync"
+)
+
+func TestIdempotentSender(t *testing.T) {
+	var uuids []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var param struct {
+			Uuid string `json:"uuid"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&param)
+		uuids = append(uuids, param.Uuid)
+		if len(uuids) == 1 {
+			http.Error(w, "gateway timeout", http.StatusGatewayTimeout)
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"ok","data":{"message_id":"om_` + param.Uuid + `"}}`))
+	}))
+
+	sender := NewIdempotentSender(client, sync.New())
+	sender.Backoff = func(attempt int) time.Duration { return 0 }
+	param := SendMessagesParam{ReceiveIdType: ReceiveIdTypeChatId, ReceiveId: "oc_1", MsgType: MsgTypeText, Content: `{"text":"hi"}`}
+
+	ctx := context.Background()
+	res, err := sender.Send(ctx, "order-1", param)
+	if err != nil || res.Data.MessageId != "om_order-1" {
+		t.Fatalf("Send() = %+v, %v", res, err)
+	}
+	res, err = sender.Send(ctx, "order-1", param)
+	if err != nil || res.Data.MessageId != "om_order-1" {
+		t.Fatalf("repeated Send() = %+v, %v", res, err)
+	}
+	if len(uuids) != 2 || uuids[0] != "order-1" || uuids[1] != "order-1" {
+		t.Errorf("uuids = %v", uuids)
+	}
+
+	longKey := strings.Repeat("k", 60)
+	if _, err = sender.Reply(ctx, longKey, ReplyMessagesParam{MessageId: "om_1", MsgType: MsgTypeText, Content: `{"text":"hi"}`}); err != nil {
+		t.Fatal(err)
+	}
+	if got := uuids[len(uuids)-1]; got != MessageUuid(longKey) || len(got) != 40 {
+		t.Errorf("reply uuid = %s", got)
+	}
+
+	if _, err = sender.Send(ctx, "", param); err != nil {
+		t.Fatal(err)
+	}
+	if got := uuids[len(uuids)-1]; len(got) != 32 {
+		t.Errorf("random uuid = %s", got)
+	}
+}
@@ -2,17 +2,28 @@ package feishu
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 )
 
+// 消息接收者 ID 类型
+const (
+	ReceiveIdTypeOpenId  = "open_id"
+	ReceiveIdTypeUserId  = "user_id"
+	ReceiveIdTypeUnionId = "union_id"
+	ReceiveIdTypeEmail   = "email"
+	ReceiveIdTypeChatId  = "chat_id"
+)
+
 // SendMessagesParam 发送消息的请求结构体
 type SendMessagesParam struct {
 	ReceiveIdType string `json:"-"`
 	ReceiveId     string `json:"receive_id"`
 	Content       string `json:"content"`
 	MsgType       string `json:"msg_type"`
+	Uuid          string `json:"uuid,omitempty"` // 幂等键，1 小时内相同 uuid 只会发送一条消息，最长 50 字符
 }
 
 // SendMessagesRes 发送消息的响应结构体
@@ -25,6 +36,7 @@ type SendMessagesResData struct {
 	MessageId      string                        `json:"message_id"`
 	RootId         string                        `json:"root_id"`
 	ParentId       string                        `json:"parent_id"`
+	ThreadId       string                        `json:"thread_id"`
 	MsgType        string                        `json:"msg_type"`
 	CreateTime     string                        `json:"create_time"`
 	UpdateTime     string                        `json:"update_time"`
@@ -86,8 +98,8 @@ type BatchSendMessagesParam struct {
 	OpenIds       []string    `json:"open_ids"`
 	UserIds       []string    `json:"user_ids"`
 	MsgType       string      `json:"msg_type"`
-	Content       interface{} `json:"content"`
-	Card          interface{} `json:"card"`
+	Content       interface{} `json:"content,omitempty"`
+	Card          *Card       `json:"card,omitempty"`
 }
 
 // BatchSendMessagesRes 批量发送消息的响应结构体
@@ -101,6 +113,11 @@ type BatchSendMessagesRes struct {
 	} `json:"data"`
 }
 
+// BatchMessageParam 返回用于查询进度与撤回的批量消息参数
+func (r *BatchSendMessagesRes) BatchMessageParam() BatchMessageParam {
+	return BatchMessageParam{BatchMessageId: r.Data.MessageId}
+}
+
 // BatchSendMessages 批量发送消息
 func (c *Client) BatchSendMessages(param BatchSendMessagesParam) (*BatchSendMessagesRes, error) {
 	jsonStr, _ := json.Marshal(param)
@@ -120,3 +137,358 @@ func (c *Client) BatchSendMessages(param BatchSendMessagesParam) (*BatchSendMess
 	}
 	return &data, err
 }
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ReplyMessagesParam 回复消息的请求结构体
+type ReplyMessagesParam struct {
+	MessageId     string `json:"-"`
+	Content       string `json:"content"`
+	MsgType       string `json:"msg_type"`
+	ReplyInThread bool   `json:"reply_in_thread,omitempty"` // 以话题形式回复，被回复的消息已在话题中时回复在该话题内
+	Uuid          string `json:"uuid,omitempty"`            // 幂等键，1 小时内相同 uuid 只会回复一条消息，最长 50 字符
+}
+
+// SetContent 设置消息内容与消息类型
+func (p *ReplyMessagesParam) SetContent(content MessageContent) error {
+	s, err := MarshalMessageContent(content)
+	if err != nil {
+		return err
+	}
+	p.MsgType = content.MsgType()
+	p.Content = s
+	return nil
+}
+
+// ReplyMessages 回复消息
+func (c *Client) ReplyMessages(param ReplyMessagesParam) (*SendMessagesRes, error) {
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/reply", strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data SendMessagesRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// UpdateMessagesParam 编辑消息的请求结构体，仅支持编辑文本与富文本消息
+type UpdateMessagesParam struct {
+	MessageId string `json:"-"`
+	Content   string `json:"content"`
+	MsgType   string `json:"msg_type"`
+}
+
+// SetContent 设置消息内容与消息类型
+func (p *UpdateMessagesParam) SetContent(content MessageContent) error {
+	s, err := MarshalMessageContent(content)
+	if err != nil {
+		return err
+	}
+	p.MsgType = content.MsgType()
+	p.Content = s
+	return nil
+}
+
+// UpdateMessages 编辑消息
+func (c *Client) UpdateMessages(param UpdateMessagesParam) (*SendMessagesRes, error) {
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPut, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId, strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data SendMessagesRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// PatchMessagesParam 更新卡片消息的请求结构体
+type PatchMessagesParam struct {
+	MessageId string `json:"-"`
+	Content   string `json:"content"`
+}
+
+// SetCard 设置更新后的卡片
+func (p *PatchMessagesParam) SetCard(card *Card) error {
+	s, err := MarshalMessageContent(card)
+	if err != nil {
+		return err
+	}
+	p.Content = s
+	return nil
+}
+
+// PatchMessagesRes 更新卡片消息的响应结构体
+type PatchMessagesRes struct {
+	ResponseCode
+}
+
+// PatchMessages 更新应用发送的消息卡片
+func (c *Client) PatchMessages(param PatchMessagesParam) (*PatchMessagesRes, error) {
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPatch, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId, strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data PatchMessagesRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// DeleteMessagesParam 撤回消息的请求结构体
+type DeleteMessagesParam struct {
+	MessageId string
+}
+
+// DeleteMessagesRes 撤回消息的响应结构体
+type DeleteMessagesRes struct {
+	ResponseCode
+}
+
+// DeleteMessages 撤回消息
+func (c *Client) DeleteMessages(param DeleteMessagesParam) (*DeleteMessagesRes, error) {
+	request, _ := http.NewRequest(http.MethodDelete, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId, nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data DeleteMessagesRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ForwardMessagesParam 转发消息的请求结构体
+type ForwardMessagesParam struct {
+	MessageId     string `json:"-"`
+	ReceiveIdType string `json:"-"`
+	ReceiveId     string `json:"receive_id"`
+}
+
+// ForwardMessages 转发消息
+func (c *Client) ForwardMessages(param ForwardMessagesParam) (*SendMessagesRes, error) {
+	params := url.Values{}
+	params.Add("receive_id_type", param.ReceiveIdType)
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/forward?"+params.Encode(), strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data SendMessagesRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ReadUsersParam 查询消息已读信息的请求结构体，只能查询机器人自己发送且不超过 7 天的消息
+type ReadUsersParam struct {
+	MessageId  string
+	UserIdType string // open_id、user_id 或 union_id，必填
+	PageSize   int64
+	PageToken  string
+}
+
+// ReadUsersRes 查询消息已读信息的响应结构体
+type ReadUsersRes struct {
+	ResponseCode
+	Data ReadUsersResData `json:"data"`
+}
+
+type ReadUsersResData struct {
+	HasMore   bool                   `json:"has_more"`
+	PageToken string                 `json:"page_token"`
+	Items     []ReadUsersResDataItem `json:"items"`
+}
+
+type ReadUsersResDataItem struct {
+	UserIdType string `json:"user_id_type"`
+	UserId     string `json:"user_id"`
+	Timestamp  string `json:"timestamp"` // 已读时间，毫秒级时间戳
+	TenantKey  string `json:"tenant_key"`
+}
+
+// ReadUsers 查询消息已读信息
+func (c *Client) ReadUsers(param ReadUsersParam) (*ReadUsersRes, error) {
+	if param.UserIdType == "" {
+		param.UserIdType = ReceiveIdTypeOpenId
+	}
+	params := url.Values{}
+	params.Add("user_id_type", param.UserIdType)
+	if param.PageSize > 0 {
+		params.Add("page_size", fmt.Sprintf("%v", param.PageSize))
+	}
+	if param.PageToken != "" {
+		params.Add("page_token", param.PageToken)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/read_users?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ReadUsersRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+// UnreadChatMembers 获取群内尚未阅读机器人所发消息的成员，用于只提醒未读的人
+func (c *Client) UnreadChatMembers(messageId, chatId, userIdType string) ([]ListChatMembersResDataItem, error) {
+	if userIdType == "" {
+		userIdType = ReceiveIdTypeOpenId
+	}
+
+	read := make(map[string]bool)
+	for pageToken := ""; ; {
+		res, err := c.ReadUsers(ReadUsersParam{MessageId: messageId, UserIdType: userIdType, PageSize: 100, PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		if res.Code != 0 {
+			return nil, fmt.Errorf("read users code %d msg %s", res.Code, res.Msg)
+		}
+		for _, item := range res.Data.Items {
+			read[item.UserId] = true
+		}
+		if !res.Data.HasMore || res.Data.PageToken == "" {
+			break
+		}
+		pageToken = res.Data.PageToken
+	}
+
+	var unread []ListChatMembersResDataItem
+	for pageToken := ""; ; {
+		res, err := c.ListChatMembers(ListChatMembersParam{ChatId: chatId, MemberIdType: userIdType, PageSize: 100, PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		if res.Code != 0 {
+			return nil, fmt.Errorf("list chat members code %d msg %s", res.Code, res.Msg)
+		}
+		for _, item := range res.Data.Items {
+			if !read[item.MemberId] {
+				unread = append(unread, item)
+			}
+		}
+		if !res.Data.HasMore || res.Data.PageToken == "" {
+			break
+		}
+		pageToken = res.Data.PageToken
+	}
+	return unread, nil
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// 加急类型
+const (
+	UrgentTypeApp   = "urgent_app"   // 应用内加急
+	UrgentTypeSms   = "urgent_sms"   // 短信加急
+	UrgentTypePhone = "urgent_phone" // 电话加急
+)
+
+// UrgentMessagesParam 加急消息的请求结构体，只能加急机器人自己发送的消息
+type UrgentMessagesParam struct {
+	MessageId  string   `json:"-"`
+	UserIdType string   `json:"-"`
+	UserIdList []string `json:"user_id_list"`
+}
+
+// UrgentMessagesRes 加急消息的响应结构体
+type UrgentMessagesRes struct {
+	ResponseCode
+	Data struct {
+		InvalidUserIdList []string `json:"invalid_user_id_list"`
+	} `json:"data"`
+}
+
+// UrgentApp 应用内加急
+func (c *Client) UrgentApp(param UrgentMessagesParam) (*UrgentMessagesRes, error) {
+	return c.urgentMessages(UrgentTypeApp, param)
+}
+
+// UrgentSms 短信加急
+func (c *Client) UrgentSms(param UrgentMessagesParam) (*UrgentMessagesRes, error) {
+	return c.urgentMessages(UrgentTypeSms, param)
+}
+
+// UrgentPhone 电话加急
+func (c *Client) UrgentPhone(param UrgentMessagesParam) (*UrgentMessagesRes, error) {
+	return c.urgentMessages(UrgentTypePhone, param)
+}
+
+func (c *Client) urgentMessages(urgentType string, param UrgentMessagesParam) (*UrgentMessagesRes, error) {
+	if param.UserIdType == "" {
+		param.UserIdType = ReceiveIdTypeOpenId
+	}
+	params := url.Values{}
+	params.Add("user_id_type", param.UserIdType)
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPatch, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/"+urgentType+"?"+params.Encode(), strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data UrgentMessagesRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
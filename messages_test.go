@@ -0,0 +1,102 @@
+package feishu
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestMessageOperations(t *testing.T) {
+	client, requests := newRecordingClient(t, `{"code":0,"data":{"message_id":"om_2"}}`)
+
+	reply := ReplyMessagesParam{MessageId: "om_1", ReplyInThread: true, Uuid: "u1"}
+	if err := reply.SetContent(NewTextContent("hi")); err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.ReplyMessages(reply)
+	if err != nil || res.Data.MessageId != "om_2" {
+		t.Fatalf("ReplyMessages() = %+v, %v", res, err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPost, "/open-apis/im/v1/messages/om_1/reply", `{"content":"{\"text\":\"hi\"}","msg_type":"text","reply_in_thread":true,"uuid":"u1"}`})
+
+	update := UpdateMessagesParam{MessageId: "om_1"}
+	if err = update.SetContent(NewTextContent("edited")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.UpdateMessages(update); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPut, "/open-apis/im/v1/messages/om_1", `{"content":"{\"text\":\"edited\"}","msg_type":"text"}`})
+
+	card, _ := NewCardBuilder().Markdown("done").Build()
+	patch := PatchMessagesParam{MessageId: "om_1"}
+	if err = patch.SetCard(card); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.PatchMessages(patch); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPatch, "/open-apis/im/v1/messages/om_1", `{"content":` + strconv.Quote(patch.Content) + `}`})
+	if !jsonEqual([]byte(patch.Content), []byte(`{"config":{"wide_screen_mode":true,"enable_forward":true},"elements":[{"tag":"markdown","content":"done"}]}`)) {
+		t.Errorf("patch content = %s", patch.Content)
+	}
+
+	if _, err = client.DeleteMessages(DeleteMessagesParam{MessageId: "om_1"}); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodDelete, "/open-apis/im/v1/messages/om_1", ""})
+
+	if _, err = client.ForwardMessages(ForwardMessagesParam{MessageId: "om_1", ReceiveIdType: ReceiveIdTypeChatId, ReceiveId: "oc_2"}); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPost, "/open-apis/im/v1/messages/om_1/forward?receive_id_type=chat_id", `{"receive_id":"oc_2"}`})
+}
+
+func TestUnreadChatMembers(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open-apis/im/v1/messages/om_1/read_users":
+			if r.URL.Query().Get("page_token") == "" {
+				_, _ = w.Write([]byte(`{"code":0,"data":{"has_more":true,"page_token":"p2","items":[{"user_id":"ou_1"}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":0,"data":{"items":[{"user_id":"ou_3"}]}}`))
+		case "/open-apis/im/v1/chats/oc_1/members":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"items":[{"member_id":"ou_1"},{"member_id":"ou_2"},{"member_id":"ou_3"}]}}`))
+		default:
+			t.Errorf("url = %s", r.URL)
+		}
+	}))
+
+	unread, err := client.UnreadChatMembers("om_1", "oc_1", "")
+	if err != nil || len(unread) != 1 || unread[0].MemberId != "ou_2" {
+		t.Errorf("UnreadChatMembers() = %+v, %v", unread, err)
+	}
+}
+
+func TestUrgentMessages(t *testing.T) {
+	client, requests := newRecordingClient(t, `{"code":0,"data":{"invalid_user_id_list":["ou_x"]}}`)
+
+	tests := []struct {
+		name   string
+		urgent func(UrgentMessagesParam) (*UrgentMessagesRes, error)
+		param  UrgentMessagesParam
+		want   apiRequest
+	}{
+		{"app", client.UrgentApp, UrgentMessagesParam{MessageId: "om_1", UserIdList: []string{"ou_1", "ou_x"}},
+			apiRequest{http.MethodPatch, "/open-apis/im/v1/messages/om_1/urgent_app?user_id_type=open_id", `{"user_id_list":["ou_1","ou_x"]}`}},
+		{"sms", client.UrgentSms, UrgentMessagesParam{MessageId: "om_1", UserIdType: ReceiveIdTypeUserId, UserIdList: []string{"u1"}},
+			apiRequest{http.MethodPatch, "/open-apis/im/v1/messages/om_1/urgent_sms?user_id_type=user_id", `{"user_id_list":["u1"]}`}},
+		{"phone", client.UrgentPhone, UrgentMessagesParam{MessageId: "om_1", UserIdList: []string{"ou_1"}},
+			apiRequest{http.MethodPatch, "/open-apis/im/v1/messages/om_1/urgent_phone?user_id_type=open_id", `{"user_id_list":["ou_1"]}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.urgent(tt.param)
+			if err != nil || len(res.Data.InvalidUserIdList) != 1 {
+				t.Fatalf("urgent = %+v, %v", res, err)
+			}
+			checkRequest(t, *requests, tt.want)
+		})
+	}
+}
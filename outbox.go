@@ -0,0 +1,481 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fengid/feishu/util"
+)
+
+// 定时消息状态
+const (
+	OutboxStatusPending  = "pending"  // 等待发送
+	OutboxStatusSending  = "sending"  // 发送中，进程在此状态退出时需通过 uuid 确认是否已送达
+	OutboxStatusSent     = "sent"     // 已发送，周期消息不会进入此状态
+	OutboxStatusFailed   = "failed"   // 重试后仍失败
+	OutboxStatusCanceled = "canceled" // 已取消
+)
+
+var (
+	ErrOutboxNotFound  = errors.New("feishu: outbox message not found")
+	ErrOutboxDuplicate = errors.New("feishu: outbox message already exists")
+)
+
+// OutboxMessage 定时消息
+type OutboxMessage struct {
+	Id            string    `json:"id"` // 幂等键，相同 Id 只会入队一次，为空时自动生成
+	ReceiveIdType string    `json:"receive_id_type"`
+	ReceiveId     string    `json:"receive_id"`
+	MsgType       string    `json:"msg_type"`
+	Content       string    `json:"content"`
+	SendAt        time.Time `json:"send_at"`        // 下次发送时间
+	Cron          string    `json:"cron,omitempty"` // 周期发送的 cron 表达式，见 ParseCron
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`       // 本次发送已失败的次数
+	Uuid          string    `json:"uuid,omitempty"` // 本次发送的幂等键，重试时保持不变
+	UuidAt        time.Time `json:"uuid_at"`        // uuid 首次使用的时间
+	LastError     string    `json:"last_error,omitempty"`
+	MessageIds    []string  `json:"message_ids,omitempty"` // 已发送的消息
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewOutboxMessage 使用消息内容创建定时消息
+func NewOutboxMessage(receiveIdType, receiveId string, content MessageContent, sendAt time.Time) (OutboxMessage, error) {
+	s, err := MarshalMessageContent(content)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	return OutboxMessage{
+		ReceiveIdType: receiveIdType,
+		ReceiveId:     receiveId,
+		MsgType:       content.MsgType(),
+		Content:       s,
+		SendAt:        sendAt,
+	}, nil
+}
+
+// Done 是否已结束，不会再发送
+func (m *OutboxMessage) Done() bool {
+	return m.Status == OutboxStatusSent || m.Status == OutboxStatusFailed || m.Status == OutboxStatusCanceled
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// OutboxStore 定时消息存储
+type OutboxStore interface {
+	// Create 保存新的定时消息，Id 已存在时返回 ErrOutboxDuplicate
+	Create(msg *OutboxMessage) error
+	// Update 更新定时消息
+	Update(msg *OutboxMessage) error
+	// Get 获取定时消息，不存在时返回 ErrOutboxNotFound
+	Get(id string) (*OutboxMessage, error)
+	// List 按状态列出定时消息，status 为空时列出全部
+	List(status string) ([]*OutboxMessage, error)
+}
+
+// FileOutboxStore 保存在本地 JSON 文件中的定时消息存储，Path 为空时仅保存在内存中
+type FileOutboxStore struct {
+	Path string
+
+	mu       sync.Mutex
+	messages map[string]*OutboxMessage
+}
+
+// NewFileOutboxStore 打开定时消息存储，文件不存在时新建
+func NewFileOutboxStore(path string) (*FileOutboxStore, error) {
+	s := &FileOutboxStore{Path: path, messages: make(map[string]*OutboxMessage)}
+	if path == "" {
+		return s, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var messages []*OutboxMessage
+	if err = json.Unmarshal(b, &messages); err != nil {
+		return nil, fmt.Errorf("feishu: outbox %s: %w", path, err)
+	}
+	for _, msg := range messages {
+		s.messages[msg.Id] = msg
+	}
+	return s, nil
+}
+
+// Create 保存新的定时消息
+func (s *FileOutboxStore) Create(msg *OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.messages[msg.Id]; ok {
+		return ErrOutboxDuplicate
+	}
+	s.messages[msg.Id] = copyOutboxMessage(msg)
+	if err := s.flush(); err != nil {
+		delete(s.messages, msg.Id)
+		return err
+	}
+	return nil
+}
+
+// Update 更新定时消息
+func (s *FileOutboxStore) Update(msg *OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.messages[msg.Id]
+	if !ok {
+		return ErrOutboxNotFound
+	}
+	s.messages[msg.Id] = copyOutboxMessage(msg)
+	if err := s.flush(); err != nil {
+		s.messages[msg.Id] = old
+		return err
+	}
+	return nil
+}
+
+// Get 获取定时消息
+func (s *FileOutboxStore) Get(id string) (*OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, ok := s.messages[id]
+	if !ok {
+		return nil, ErrOutboxNotFound
+	}
+	return copyOutboxMessage(msg), nil
+}
+
+// List 按状态列出定时消息，按发送时间排序
+func (s *FileOutboxStore) List(status string) ([]*OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*OutboxMessage
+	for _, msg := range s.messages {
+		if status == "" || msg.Status == status {
+			list = append(list, copyOutboxMessage(msg))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SendAt.Equal(list[j].SendAt) {
+			return list[i].Id < list[j].Id
+		}
+		return list[i].SendAt.Before(list[j].SendAt)
+	})
+	return list, nil
+}
+
+// flush 写入临时文件后重命名，避免进程退出时文件损坏
+func (s *FileOutboxStore) flush() error {
+	if s.Path == "" {
+		return nil
+	}
+	messages := make([]*OutboxMessage, 0, len(s.messages))
+	for _, msg := range s.messages {
+		messages = append(messages, msg)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Id < messages[j].Id })
+	b, err := json.MarshalIndent(messages, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+func copyOutboxMessage(msg *OutboxMessage) *OutboxMessage {
+	c := *msg
+	c.MessageIds = append([]string(nil), msg.MessageIds...)
+	return &c
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// Outbox 定时消息发送器，基于 SendMessages 按时间发送存储中的消息
+//
+// 每次发送使用固定的 uuid，重试与进程重启后恢复发送时不会重复发送
+type Outbox struct {
+	Client     *Client
+	Store      OutboxStore
+	Interval   time.Duration                   // 检查到期消息的间隔，默认 1 秒
+	MaxRetries int                             // 发送失败后的最大重试次数，默认 3 次
+	Backoff    func(attempt int) time.Duration // 重试间隔，默认指数退避
+	Location   *time.Location                  // cron 表达式使用的时区，默认 time.Local
+
+	// OnResult 每次发送后回调，err 为空表示发送成功
+	OnResult func(msg *OutboxMessage, err error)
+
+	Now func() time.Time // 用于测试，默认 time.Now
+
+	mu sync.Mutex // 保证状态变更的读写一致
+}
+
+// NewOutbox 创建定时消息发送器
+func NewOutbox(client *Client, store OutboxStore) *Outbox {
+	return &Outbox{
+		Client:     client,
+		Store:      store,
+		Interval:   time.Second,
+		MaxRetries: 3,
+		Backoff:    defaultBackoff,
+		Location:   time.Local,
+	}
+}
+
+func (o *Outbox) now() time.Time {
+	now := time.Now()
+	if o.Now != nil {
+		now = o.Now()
+	}
+	if o.Location != nil {
+		now = now.In(o.Location)
+	}
+	return now
+}
+
+// Schedule 添加定时消息
+//
+// Id 已存在时不会重复添加，返回已有的消息与 ErrOutboxDuplicate；
+// 设置 Cron 且 SendAt 为空时，从下一个匹配的时间开始发送
+func (o *Outbox) Schedule(msg OutboxMessage) (*OutboxMessage, error) {
+	if msg.ReceiveId == "" || msg.MsgType == "" || msg.Content == "" {
+		return nil, errors.New("feishu: outbox message requires receive_id, msg_type and content")
+	}
+	now := o.now()
+	if msg.Cron != "" {
+		schedule, err := ParseCron(msg.Cron)
+		if err != nil {
+			return nil, err
+		}
+		if msg.SendAt.IsZero() {
+			msg.SendAt = schedule.Next(now)
+		}
+	}
+	if msg.SendAt.IsZero() {
+		msg.SendAt = now
+	}
+	if msg.Id == "" {
+		msg.Id = util.GetRandString(32)
+	}
+	msg.Status = OutboxStatusPending
+	msg.Attempts = 0
+	msg.LastError = ""
+	msg.MessageIds = nil
+	msg.CreatedAt = now
+	msg.UpdatedAt = now
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.Store.Create(&msg); err != nil {
+		if err == ErrOutboxDuplicate {
+			existing, getErr := o.Store.Get(msg.Id)
+			if getErr != nil {
+				return nil, getErr
+			}
+			return existing, err
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// Cancel 取消定时消息，已结束的消息返回其当前状态
+func (o *Outbox) Cancel(id string) (*OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	msg, err := o.Store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Done() {
+		return msg, nil
+	}
+	if msg.Status == OutboxStatusSending {
+		return msg, fmt.Errorf("feishu: outbox message %s is being sent", id)
+	}
+	msg.Status = OutboxStatusCanceled
+	msg.UpdatedAt = o.now()
+	return msg, o.Store.Update(msg)
+}
+
+// Get 获取定时消息
+func (o *Outbox) Get(id string) (*OutboxMessage, error) {
+	return o.Store.Get(id)
+}
+
+// Run 循环发送到期的消息，直到 ctx 取消
+func (o *Outbox) Run(ctx context.Context) error {
+	if err := o.Recover(); err != nil {
+		return err
+	}
+	interval := o.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := o.RunDue(ctx); err != nil && Logger != nil {
+			Logger.Printf("outbox error %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Recover 处理上次退出时处于 sending 状态的消息
+//
+// uuid 仍在平台去重窗口内的消息重新发送；超出窗口的无法确认是否已送达，
+// 为避免重复发送，一次性消息标记为失败，周期消息进入下一周期
+func (o *Outbox) Recover() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	list, err := o.Store.List(OutboxStatusSending)
+	if err != nil {
+		return err
+	}
+	now := o.now()
+	for _, msg := range list {
+		if now.Sub(msg.UuidAt) < MessageUuidWindow {
+			msg.Status = OutboxStatusPending
+		} else {
+			o.finish(msg, errors.New("interrupted while sending, delivery unknown"))
+		}
+		msg.UpdatedAt = now
+		if err = o.Store.Update(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDue 发送所有到期的消息
+func (o *Outbox) RunDue(ctx context.Context) error {
+	list, err := o.Store.List(OutboxStatusPending)
+	if err != nil {
+		return err
+	}
+	for _, msg := range list {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if msg.SendAt.After(o.now()) {
+			break
+		}
+		if err = o.send(msg.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send 发送一条消息，返回的错误仅为存储错误
+func (o *Outbox) send(id string) error {
+	o.mu.Lock()
+	msg, err := o.Store.Get(id)
+	if err != nil || msg.Status != OutboxStatusPending {
+		o.mu.Unlock()
+		return err
+	}
+	msg.Status = OutboxStatusSending
+	msg.UpdatedAt = o.now()
+	if msg.Uuid == "" {
+		msg.Uuid = MessageUuid(fmt.Sprintf("%s@%d", msg.Id, msg.SendAt.Unix()))
+		msg.UuidAt = msg.UpdatedAt
+	}
+	err = o.Store.Update(msg)
+	o.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	res, sendErr := o.Client.SendMessages(SendMessagesParam{
+		ReceiveIdType: msg.ReceiveIdType,
+		ReceiveId:     msg.ReceiveId,
+		Content:       msg.Content,
+		MsgType:       msg.MsgType,
+		Uuid:          msg.Uuid,
+	})
+	if sendErr == nil && res.Code != 0 {
+		sendErr = fmt.Errorf("send outbox message %s code %d msg %s", msg.Id, res.Code, res.Msg)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if sendErr == nil {
+		msg.MessageIds = append(msg.MessageIds, res.Data.MessageId)
+		o.finish(msg, nil)
+	} else {
+		msg.Attempts++
+		if msg.Attempts > o.MaxRetries {
+			o.finish(msg, sendErr)
+		} else {
+			backoff := o.Backoff
+			if backoff == nil {
+				backoff = defaultBackoff
+			}
+			msg.Status = OutboxStatusPending
+			msg.LastError = sendErr.Error()
+			msg.SendAt = o.now().Add(backoff(msg.Attempts - 1))
+		}
+	}
+	msg.UpdatedAt = o.now()
+	if err = o.Store.Update(msg); err != nil {
+		return err
+	}
+	if o.OnResult != nil {
+		o.OnResult(msg, sendErr)
+	}
+	return nil
+}
+
+// finish 结束本次发送，周期消息进入下一周期
+func (o *Outbox) finish(msg *OutboxMessage, err error) {
+	msg.Uuid = ""
+	msg.UuidAt = time.Time{}
+	msg.LastError = ""
+	if err != nil {
+		msg.LastError = err.Error()
+	}
+	if msg.Cron != "" {
+		if schedule, parseErr := ParseCron(msg.Cron); parseErr == nil {
+			if next := schedule.Next(o.now()); !next.IsZero() {
+				msg.Status = OutboxStatusPending
+				msg.SendAt = next
+				msg.Attempts = 0
+				return
+			}
+		}
+	}
+	if err != nil {
+		msg.Status = OutboxStatusFailed
+	} else {
+		msg.Status = OutboxStatusSent
+		msg.Attempts = 0
+	}
+}
@@ -0,0 +1,128 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOutbox(t *testing.T) {
+	var sent, fail int32
+	var lastUuid atomic.Value
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var param SendMessagesParam
+		_ = json.NewDecoder(r.Body).Decode(&param)
+		lastUuid.Store(param.Uuid)
+		if atomic.LoadInt32(&fail) > 0 {
+			atomic.AddInt32(&fail, -1)
+			_, _ = w.Write([]byte(`{"code":99991400,"msg":"request trigger frequency limit"}`))
+			return
+		}
+		atomic.AddInt32(&sent, 1)
+		_, _ = w.Write([]byte(`{"code":0,"msg":"ok","data":{"message_id":"om_1"}}`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	store, err := NewFileOutboxStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2021, 3, 5, 10, 0, 0, 0, time.UTC)
+	outbox := NewOutbox(client, store)
+	outbox.Location = time.UTC
+	outbox.Now = func() time.Time { return now }
+	outbox.Backoff = func(attempt int) time.Duration { return time.Minute }
+
+	msg, _ := NewOutboxMessage(ReceiveIdTypeChatId, "oc_1", &TextContent{Text: "remind"}, now.Add(time.Hour))
+	msg.Id = "remind-1"
+	if _, err = outbox.Schedule(msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = outbox.Schedule(msg); !errors.Is(err, ErrOutboxDuplicate) {
+		t.Errorf("duplicate Schedule error = %v", err)
+	}
+	daily := msg
+	daily.Id, daily.SendAt, daily.Cron = "daily", time.Time{}, "0 12 * * *"
+	if _, err = outbox.Schedule(daily); err != nil {
+		t.Fatal(err)
+	}
+	canceled := msg
+	canceled.Id = "canceled"
+	_, _ = outbox.Schedule(canceled)
+	if got, err := outbox.Cancel("canceled"); err != nil || got.Status != OutboxStatusCanceled {
+		t.Errorf("Cancel() = %+v, %v", got, err)
+	}
+
+	ctx := context.Background()
+	_ = outbox.RunDue(ctx)
+	if sent != 0 {
+		t.Fatalf("sent %d messages before due", sent)
+	}
+
+	// 到期后第一次发送失败，退避后重试成功
+	now = now.Add(time.Hour)
+	atomic.StoreInt32(&fail, 1)
+	_ = outbox.RunDue(ctx)
+	got, _ := outbox.Get("remind-1")
+	if got.Status != OutboxStatusPending || got.Attempts != 1 || !got.SendAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("after failure = %+v", got)
+	}
+	now = now.Add(time.Minute)
+	_ = outbox.RunDue(ctx)
+	_ = outbox.RunDue(ctx)
+	got, _ = outbox.Get("remind-1")
+	if got.Status != OutboxStatusSent || len(got.MessageIds) != 1 || sent != 1 {
+		t.Errorf("after retry = %+v, sent %d", got, sent)
+	}
+
+	// 周期消息发送后进入下一周期
+	now = time.Date(2021, 3, 5, 12, 0, 30, 0, time.UTC)
+	_ = outbox.RunDue(ctx)
+	got, _ = outbox.Get("daily")
+	if got.Status != OutboxStatusPending || !got.SendAt.Equal(time.Date(2021, 3, 6, 12, 0, 0, 0, time.UTC)) || sent != 2 {
+		t.Errorf("daily = %+v, sent %d", got, sent)
+	}
+
+	// 模拟发送过程中进程退出，去重窗口内使用原 uuid 重新发送，超出窗口的不再发送
+	for _, id := range []string{"interrupted", "expired"} {
+		interrupted := msg
+		interrupted.Id = id
+		_, _ = outbox.Schedule(interrupted)
+		pending, _ := store.Get(id)
+		pending.Status = OutboxStatusSending
+		pending.Uuid = id + "-uuid"
+		pending.UuidAt = now.Add(-time.Minute)
+		if id == "expired" {
+			pending.UuidAt = now.Add(-MessageUuidWindow)
+		}
+		_ = store.Update(pending)
+	}
+
+	reopened, err := NewFileOutboxStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted := NewOutbox(client, reopened)
+	restarted.Now = outbox.Now
+	if err = restarted.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	_ = restarted.RunDue(ctx)
+	if got, _ = restarted.Get("interrupted"); got.Status != OutboxStatusSent || lastUuid.Load() != "interrupted-uuid" {
+		t.Errorf("interrupted = %+v, uuid %v", got, lastUuid.Load())
+	}
+	if got, _ = restarted.Get("expired"); got.Status != OutboxStatusFailed {
+		t.Errorf("expired = %+v", got)
+	}
+	if got, _ = restarted.Get("canceled"); got.Status != OutboxStatusCanceled {
+		t.Errorf("canceled = %+v", got)
+	}
+	if sent != 3 {
+		t.Errorf("sent %d messages, want 3", sent)
+	}
+}
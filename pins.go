@@ -0,0 +1,140 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MessagePin 群内置顶（Pin）的消息
+type MessagePin struct {
+	MessageId      string `json:"message_id"`
+	ChatId         string `json:"chat_id"`
+	OperatorId     string `json:"operator_id"`
+	OperatorIdType string `json:"operator_id_type"`
+	CreateTime     string `json:"create_time"`
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// CreatePinsParam Pin 消息的请求结构体
+type CreatePinsParam struct {
+	MessageId string `json:"message_id"`
+}
+
+// CreatePinsRes Pin 消息的响应结构体
+type CreatePinsRes struct {
+	ResponseCode
+	Data struct {
+		Pin MessagePin `json:"pin"`
+	} `json:"data"`
+}
+
+// CreatePins Pin 消息
+func (c *Client) CreatePins(param CreatePinsParam) (*CreatePinsRes, error) {
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/pins", strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data CreatePinsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// DeletePinsParam 移除 Pin 消息的请求结构体
+type DeletePinsParam struct {
+	MessageId string
+}
+
+// DeletePinsRes 移除 Pin 消息的响应结构体
+type DeletePinsRes struct {
+	ResponseCode
+}
+
+// DeletePins 移除 Pin 消息
+func (c *Client) DeletePins(param DeletePinsParam) (*DeletePinsRes, error) {
+	request, _ := http.NewRequest(http.MethodDelete, ServerUrl+"/open-apis/im/v1/pins/"+param.MessageId, nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data DeletePinsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ListPinsParam 获取群内 Pin 消息的请求结构体
+type ListPinsParam struct {
+	ChatId    string
+	StartTime string // 毫秒级时间戳
+	EndTime   string
+	PageSize  int64
+	PageToken string
+}
+
+// ListPinsRes 获取群内 Pin 消息的响应结构体
+type ListPinsRes struct {
+	ResponseCode
+	Data ListPinsResData `json:"data"`
+}
+
+type ListPinsResData struct {
+	HasMore   bool         `json:"has_more"`
+	PageToken string       `json:"page_token"`
+	Items     []MessagePin `json:"items"`
+}
+
+// ListPins 获取群内 Pin 消息，按 Pin 的创建时间降序排列
+func (c *Client) ListPins(param ListPinsParam) (*ListPinsRes, error) {
+	params := url.Values{}
+	params.Add("chat_id", param.ChatId)
+	if param.StartTime != "" {
+		params.Add("start_time", param.StartTime)
+	}
+	if param.EndTime != "" {
+		params.Add("end_time", param.EndTime)
+	}
+	if param.PageSize > 0 {
+		params.Add("page_size", fmt.Sprintf("%v", param.PageSize))
+	}
+	if param.PageToken != "" {
+		params.Add("page_token", param.PageToken)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/pins?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ListPinsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
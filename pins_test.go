@@ -0,0 +1,27 @@
+package feishu
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPins(t *testing.T) {
+	client, requests := newRecordingClient(t, `{"code":0,"data":{"pin":{"message_id":"om_1","chat_id":"oc_1"},"items":[{"message_id":"om_1"}]}}`)
+
+	res, err := client.CreatePins(CreatePinsParam{MessageId: "om_1"})
+	if err != nil || res.Data.Pin.ChatId != "oc_1" {
+		t.Fatalf("CreatePins() = %+v, %v", res, err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPost, "/open-apis/im/v1/pins", `{"message_id":"om_1"}`})
+
+	if _, err = client.DeletePins(DeletePinsParam{MessageId: "om_1"}); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodDelete, "/open-apis/im/v1/pins/om_1", ""})
+
+	list, err := client.ListPins(ListPinsParam{ChatId: "oc_1", StartTime: "1600000000000", PageToken: "p2"})
+	if err != nil || len(list.Data.Items) != 1 {
+		t.Fatalf("ListPins() = %+v, %v", list, err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodGet, "/open-apis/im/v1/pins?chat_id=oc_1&page_token=p2&start_time=1600000000000", ""})
+}
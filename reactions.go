@@ -0,0 +1,156 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// 常用表情类型，完整列表见飞书开放平台表情文案说明
+const (
+	EmojiTypeOk        = "OK"
+	EmojiTypeThumbsUp  = "THUMBSUP"
+	EmojiTypeThanks    = "THANKS"
+	EmojiTypeMuscle    = "MUSCLE"
+	EmojiTypeDone      = "DONE"
+	EmojiTypeSmile     = "SMILE"
+	EmojiTypeHeart     = "HEART"
+	EmojiTypeApplause  = "APPLAUSE"
+	EmojiTypeGet       = "Get"
+	EmojiTypeJiaYi     = "JIAYI"
+	EmojiTypeCrossMark = "CrossMark"
+)
+
+// MessageReactionType 表情类型
+type MessageReactionType struct {
+	EmojiType string `json:"emoji_type"`
+}
+
+// MessageReaction 消息表情回复
+type MessageReaction struct {
+	ReactionId string `json:"reaction_id"`
+	Operator   struct {
+		OperatorId   string `json:"operator_id"`
+		OperatorType string `json:"operator_type"` // app 或 user
+	} `json:"operator"`
+	ActionTime   string              `json:"action_time"`
+	ReactionType MessageReactionType `json:"reaction_type"`
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// CreateMessageReactionsParam 添加消息表情回复的请求结构体
+type CreateMessageReactionsParam struct {
+	MessageId    string              `json:"-"`
+	ReactionType MessageReactionType `json:"reaction_type"`
+}
+
+// MessageReactionsRes 添加、删除消息表情回复的响应结构体
+type MessageReactionsRes struct {
+	ResponseCode
+	Data MessageReaction `json:"data"`
+}
+
+// CreateMessageReactions 添加消息表情回复
+func (c *Client) CreateMessageReactions(param CreateMessageReactionsParam) (*MessageReactionsRes, error) {
+	jsonStr, _ := json.Marshal(param)
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/reactions", strings.NewReader(string(jsonStr)))
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data MessageReactionsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// DeleteMessageReactionsParam 删除消息表情回复的请求结构体，只能删除当前身份添加的表情
+type DeleteMessageReactionsParam struct {
+	MessageId  string
+	ReactionId string
+}
+
+// DeleteMessageReactions 删除消息表情回复
+func (c *Client) DeleteMessageReactions(param DeleteMessageReactionsParam) (*MessageReactionsRes, error) {
+	request, _ := http.NewRequest(http.MethodDelete, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/reactions/"+param.ReactionId, nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data MessageReactionsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// ListMessageReactionsParam 获取消息表情回复的请求结构体
+type ListMessageReactionsParam struct {
+	MessageId    string
+	ReactionType string // 表情类型，为空时返回全部
+	UserIdType   string
+	PageSize     int64
+	PageToken    string
+}
+
+// ListMessageReactionsRes 获取消息表情回复的响应结构体
+type ListMessageReactionsRes struct {
+	ResponseCode
+	Data ListMessageReactionsResData `json:"data"`
+}
+
+type ListMessageReactionsResData struct {
+	HasMore   bool              `json:"has_more"`
+	PageToken string            `json:"page_token"`
+	Items     []MessageReaction `json:"items"`
+}
+
+// ListMessageReactions 获取消息表情回复
+func (c *Client) ListMessageReactions(param ListMessageReactionsParam) (*ListMessageReactionsRes, error) {
+	params := url.Values{}
+	if param.ReactionType != "" {
+		params.Add("reaction_type", param.ReactionType)
+	}
+	if param.UserIdType != "" {
+		params.Add("user_id_type", param.UserIdType)
+	}
+	if param.PageSize > 0 {
+		params.Add("page_size", fmt.Sprintf("%v", param.PageSize))
+	}
+	if param.PageToken != "" {
+		params.Add("page_token", param.PageToken)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/im/v1/messages/"+param.MessageId+"/reactions?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data ListMessageReactionsRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
@@ -0,0 +1,27 @@
+package feishu
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMessageReactions(t *testing.T) {
+	client, requests := newRecordingClient(t, `{"code":0,"data":{"reaction_id":"r1","reaction_type":{"emoji_type":"DONE"},"items":[{"reaction_id":"r1"}]}}`)
+
+	res, err := client.CreateMessageReactions(CreateMessageReactionsParam{MessageId: "om_1", ReactionType: MessageReactionType{EmojiType: EmojiTypeDone}})
+	if err != nil || res.Data.ReactionId != "r1" || res.Data.ReactionType.EmojiType != EmojiTypeDone {
+		t.Fatalf("CreateMessageReactions() = %+v, %v", res, err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodPost, "/open-apis/im/v1/messages/om_1/reactions", `{"reaction_type":{"emoji_type":"DONE"}}`})
+
+	if _, err = client.DeleteMessageReactions(DeleteMessageReactionsParam{MessageId: "om_1", ReactionId: "r1"}); err != nil {
+		t.Fatal(err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodDelete, "/open-apis/im/v1/messages/om_1/reactions/r1", ""})
+
+	list, err := client.ListMessageReactions(ListMessageReactionsParam{MessageId: "om_1", ReactionType: EmojiTypeDone, PageSize: 20})
+	if err != nil || len(list.Data.Items) != 1 {
+		t.Fatalf("ListMessageReactions() = %+v, %v", list, err)
+	}
+	checkRequest(t, *requests, apiRequest{http.MethodGet, "/open-apis/im/v1/messages/om_1/reactions?page_size=20&reaction_type=DONE", ""})
+}
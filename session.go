@@ -0,0 +1,277 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/faabiosr/cachego"
+)
+
+var ErrSessionNotFound = errors.New("feishu: session not found")
+
+// Session 多轮对话会话，按会话 ID 与用户 open_id 唯一
+type Session struct {
+	ChatId     string          `json:"chat_id"`
+	OpenId     string          `json:"open_id"`
+	Step       string          `json:"step"`
+	State      json.RawMessage `json:"state,omitempty"`
+	MessageIds []string        `json:"message_ids,omitempty"` // 绑定的卡片消息，卡片回调据此恢复会话
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+
+	store *SessionStore
+}
+
+// Decode 读取会话状态
+func (s *Session) Decode(v interface{}) error {
+	if len(s.State) == 0 {
+		return nil
+	}
+	return json.Unmarshal(s.State, v)
+}
+
+// SetState 设置会话状态，需调用 Save 保存
+func (s *Session) SetState(v interface{}) error {
+	state, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.State = state
+	return nil
+}
+
+// Next 进入下一步并保存，state 为 nil 时保留原状态
+func (s *Session) Next(step string, state interface{}) error {
+	if state != nil {
+		if err := s.SetState(state); err != nil {
+			return err
+		}
+	}
+	s.Step = step
+	return s.Save()
+}
+
+// BindMessage 绑定卡片消息并保存，之后该卡片的回调可以恢复会话
+func (s *Session) BindMessage(messageId string) error {
+	for _, id := range s.MessageIds {
+		if id == messageId {
+			return s.Save()
+		}
+	}
+	s.MessageIds = append(s.MessageIds, messageId)
+	return s.Save()
+}
+
+// Save 保存会话并刷新过期时间
+func (s *Session) Save() error {
+	return s.store.save(s)
+}
+
+// End 结束会话
+func (s *Session) End() error {
+	return s.store.Delete(s.ChatId, s.OpenId)
+}
+
+// SessionStore 会话存储，使用 cachego.Cache 持久化，可选用 redis 等实现以便重启后恢复对话
+type SessionStore struct {
+	Cache  cachego.Cache
+	TTL    time.Duration // 会话空闲过期时间，每次保存时刷新
+	Prefix string
+}
+
+// NewSessionStore 创建会话存储
+func NewSessionStore(cache cachego.Cache, ttl time.Duration) *SessionStore {
+	return &SessionStore{Cache: cache, TTL: ttl, Prefix: "feishu:session:"}
+}
+
+// Start 开始新的会话，已有的会话会被覆盖
+func (s *SessionStore) Start(chatId, openId, step string, state interface{}) (*Session, error) {
+	if old, err := s.Get(chatId, openId); err == nil {
+		_ = s.deleteMessages(old)
+	}
+	now := time.Now()
+	session := &Session{ChatId: chatId, OpenId: openId, Step: step, CreatedAt: now, store: s}
+	if state != nil {
+		if err := session.SetState(state); err != nil {
+			return nil, err
+		}
+	}
+	return session, session.Save()
+}
+
+// Get 获取会话，不存在或已过期时返回 ErrSessionNotFound
+func (s *SessionStore) Get(chatId, openId string) (*Session, error) {
+	return s.fetch(s.sessionKey(chatId, openId))
+}
+
+// GetByMessage 通过绑定的卡片消息获取会话
+func (s *SessionStore) GetByMessage(messageId string) (*Session, error) {
+	key := s.Prefix + "message:" + messageId
+	if !s.Cache.Contains(key) {
+		return nil, ErrSessionNotFound
+	}
+	sessionKey, err := s.Cache.Fetch(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.fetch(sessionKey)
+}
+
+// ForMessage 获取接收消息的发送者在该会话中的对话
+func (s *SessionStore) ForMessage(data *MessageReceiveEvent) (*Session, error) {
+	return s.Get(data.Message.ChatId, data.Sender.SenderId.OpenId)
+}
+
+// ForCardAction 获取卡片回调对应的对话，优先按卡片消息查找，其次按会话与用户查找
+//
+// 群聊中其他人点击绑定的卡片时返回 ErrSessionNotFound，不能推进他人的对话
+func (s *SessionStore) ForCardAction(action *CardAction) (*Session, error) {
+	session, err := s.GetByMessage(action.OpenMessageId)
+	if err == nil && session.OpenId != action.OpenId {
+		return nil, ErrSessionNotFound
+	}
+	if err != ErrSessionNotFound {
+		return session, err
+	}
+	return s.Get(action.OpenChatId, action.OpenId)
+}
+
+// Delete 删除会话及其绑定的卡片消息
+func (s *SessionStore) Delete(chatId, openId string) error {
+	session, err := s.Get(chatId, openId)
+	if err == ErrSessionNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = s.deleteMessages(session); err != nil {
+		return err
+	}
+	return s.Cache.Delete(s.sessionKey(chatId, openId))
+}
+
+func (s *SessionStore) sessionKey(chatId, openId string) string {
+	return s.Prefix + chatId + ":" + openId
+}
+
+func (s *SessionStore) fetch(key string) (*Session, error) {
+	if !s.Cache.Contains(key) {
+		return nil, ErrSessionNotFound
+	}
+	value, err := s.Cache.Fetch(key)
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err = json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, err
+	}
+	session.store = s
+	return &session, nil
+}
+
+func (s *SessionStore) save(session *Session) error {
+	session.UpdatedAt = time.Now()
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	key := s.sessionKey(session.ChatId, session.OpenId)
+	if err = s.Cache.Save(key, string(value), s.TTL); err != nil {
+		return err
+	}
+	for _, messageId := range session.MessageIds {
+		if err = s.Cache.Save(s.Prefix+"message:"+messageId, key, s.TTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SessionStore) deleteMessages(session *Session) error {
+	for _, messageId := range session.MessageIds {
+		if err := s.Cache.Delete(s.Prefix + "message:" + messageId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SessionInput 恢复会话的输入，文本回复与卡片回调二选一
+type SessionInput struct {
+	Event   *Event
+	Message *MessageReceiveEvent
+	Text    string // 文本消息内容，@ 占位符已替换为用户名
+	Action  *CardAction
+}
+
+// SessionStepHandler 会话步骤处理函数，卡片回调时可返回新的卡片
+type SessionStepHandler func(ctx context.Context, session *Session, input *SessionInput) (*CardActionResponse, error)
+
+// SessionFlow 按会话步骤分发文本回复与卡片回调
+//
+// 同一会话的事件需按顺序处理，异步分发时 AsyncDispatcher 已按会话保证顺序
+type SessionFlow struct {
+	Store *SessionStore
+
+	mu    sync.RWMutex
+	steps map[string]SessionStepHandler
+}
+
+// NewSessionFlow 创建会话流程
+func NewSessionFlow(store *SessionStore) *SessionFlow {
+	return &SessionFlow{Store: store, steps: make(map[string]SessionStepHandler)}
+}
+
+// Step 注册步骤处理函数
+func (f *SessionFlow) Step(step string, handler SessionStepHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.steps[step] = handler
+}
+
+func (f *SessionFlow) handler(step string) SessionStepHandler {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.steps[step]
+}
+
+// WrapMessage 有进行中的会话时由会话处理文本回复，否则交给 next，如命令路由
+func (f *SessionFlow) WrapMessage(next func(ctx context.Context, event *Event, data *MessageReceiveEvent) error) func(ctx context.Context, event *Event, data *MessageReceiveEvent) error {
+	return func(ctx context.Context, event *Event, data *MessageReceiveEvent) error {
+		session, err := f.Store.ForMessage(data)
+		if err == ErrSessionNotFound || (err == nil && f.handler(session.Step) == nil) {
+			if next == nil {
+				return nil
+			}
+			return next(ctx, event, data)
+		}
+		if err != nil {
+			return err
+		}
+		input := &SessionInput{Event: event, Message: data, Text: data.Message.Render().Text}
+		_, err = f.handler(session.Step)(ctx, session, input)
+		return err
+	}
+}
+
+// WrapCardAction 卡片回调对应进行中的会话时由会话处理，否则交给 next
+func (f *SessionFlow) WrapCardAction(next CardActionHandlerFunc) CardActionHandlerFunc {
+	return func(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+		session, err := f.Store.ForCardAction(action)
+		if err == ErrSessionNotFound || (err == nil && f.handler(session.Step) == nil) {
+			if next == nil {
+				return nil, nil
+			}
+			return next(ctx, action)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return f.handler(session.Step)(ctx, session, &SessionInput{Action: action})
+	}
+}
@@ -0,0 +1,81 @@
+package feishu
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/faabiosr/cachego/sync"
+)
+
+func TestSessionFlow(t *testing.T) {
+	store := NewSessionStore(sync.New(), time.Minute)
+	flow := NewSessionFlow(store)
+
+	type approval struct {
+		Reason string
+		Days   int
+	}
+	flow.Step("reason", func(ctx context.Context, session *Session, input *SessionInput) (*CardActionResponse, error) {
+		if err := session.BindMessage("om_card"); err != nil {
+			return nil, err
+		}
+		return nil, session.Next("confirm", approval{Reason: input.Text, Days: 3})
+	})
+	flow.Step("confirm", func(ctx context.Context, session *Session, input *SessionInput) (*CardActionResponse, error) {
+		var state approval
+		if err := session.Decode(&state); err != nil {
+			return nil, err
+		}
+		if input.Action == nil || state.Reason != "家里有事" || state.Days != 3 {
+			t.Errorf("confirm input = %+v, state = %+v", input, state)
+		}
+		return &CardActionResponse{Toast: &CardToast{Type: ToastSuccess, Content: "已提交"}}, session.End()
+	})
+
+	var fallthroughs int
+	onMessage := flow.WrapMessage(func(ctx context.Context, event *Event, data *MessageReceiveEvent) error {
+		fallthroughs++
+		return nil
+	})
+	message := &MessageReceiveEvent{
+		Sender:  MessageReceiveEventSender{SenderId: EventUserId{OpenId: "ou_1"}},
+		Message: MessageReceiveEventMessage{ChatId: "oc_1", MessageType: MsgTypeText, Content: `{"text":"家里有事"}`},
+	}
+
+	if err := onMessage(context.Background(), nil, message); err != nil || fallthroughs != 1 {
+		t.Fatalf("no session: err = %v, fallthroughs = %d", err, fallthroughs)
+	}
+	if _, err := store.Start("oc_1", "ou_1", "reason", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := onMessage(context.Background(), nil, message); err != nil || fallthroughs != 1 {
+		t.Fatalf("reason step: err = %v, fallthroughs = %d", err, fallthroughs)
+	}
+
+	// 其他用户点击绑定的卡片不会推进对话，交给 next 处理
+	var actionFallthroughs int
+	onAction := flow.WrapCardAction(func(ctx context.Context, action *CardAction) (*CardActionResponse, error) {
+		actionFallthroughs++
+		return nil, nil
+	})
+	res, err := onAction(context.Background(), &CardAction{OpenId: "ou_2", OpenChatId: "oc_1", OpenMessageId: "om_card"})
+	if err != nil || res != nil || actionFallthroughs != 1 {
+		t.Fatalf("other user action = %+v, %v, fallthroughs = %d", res, err, actionFallthroughs)
+	}
+	if session, err := store.Get("oc_1", "ou_1"); err != nil || session.Step != "confirm" {
+		t.Fatalf("session after other user action = %+v, %v", session, err)
+	}
+
+	// 卡片回调通过卡片消息 ID 恢复会话，即使回调中没有 chat_id
+	res, err = flow.WrapCardAction(nil)(context.Background(), &CardAction{OpenId: "ou_1", OpenMessageId: "om_card"})
+	if err != nil || res == nil || res.Toast.Content != "已提交" {
+		t.Fatalf("confirm step = %+v, %v", res, err)
+	}
+	if _, err := store.Get("oc_1", "ou_1"); err != ErrSessionNotFound {
+		t.Errorf("Get() after End error = %v", err)
+	}
+	if _, err := store.GetByMessage("om_card"); err != ErrSessionNotFound {
+		t.Errorf("GetByMessage() after End error = %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package feishu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrListThreadsUnbounded = errors.New("feishu: list threads requires StartTime or MaxPages")
+
+// ListThreadsParam 获取会话中话题的请求结构体
+//
+// 需要遍历会话中的消息，StartTime 与 MaxPages 至少设置一个，避免遍历整个会话历史
+type ListThreadsParam struct {
+	ChatId    string
+	StartTime string // 起始时间，秒级时间戳
+	EndTime   string // 结束时间，秒级时间戳
+	MaxPages  int    // 最多请求的消息页数，每页 50 条
+}
+
+// ListThreads 获取会话中的话题，返回各话题的根消息，按创建时间排序
+func (c *Client) ListThreads(ctx context.Context, param ListThreadsParam) ([]MessageResDataItem, error) {
+	if param.StartTime == "" && param.MaxPages <= 0 {
+		return nil, ErrListThreadsUnbounded
+	}
+	var threads []MessageResDataItem
+	seen := make(map[string]bool)
+	err := c.listAllMessages(ctx, ListMessagesParam{
+		ContainerIdType: ContainerIdTypeChat,
+		ContainerId:     param.ChatId,
+		StartTime:       param.StartTime,
+		EndTime:         param.EndTime,
+	}, param.MaxPages, func(item MessageResDataItem) error {
+		if item.ThreadId == "" || seen[item.ThreadId] {
+			return nil
+		}
+		if item.RootId == "" || item.RootId == item.MessageId {
+			seen[item.ThreadId] = true
+			threads = append(threads, item)
+		}
+		return nil
+	})
+	return threads, err
+}
+
+// ThreadMessages 获取话题中的全部消息，包含根消息，按创建时间排序
+func (c *Client) ThreadMessages(ctx context.Context, threadId string) ([]MessageResDataItem, error) {
+	var items []MessageResDataItem
+	err := c.listAllMessages(ctx, ListMessagesParam{
+		ContainerIdType: ContainerIdTypeThread,
+		ContainerId:     threadId,
+	}, 0, func(item MessageResDataItem) error {
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// ThreadMessagesByRoot 通过话题根消息获取话题中的全部消息
+func (c *Client) ThreadMessagesByRoot(ctx context.Context, rootId string) ([]MessageResDataItem, error) {
+	res, err := c.Messages(MessageParam{MessageId: rootId})
+	if err != nil {
+		return nil, err
+	}
+	if res.Code != 0 {
+		return nil, fmt.Errorf("get message code %d msg %s", res.Code, res.Msg)
+	}
+	if len(res.Data.Items) == 0 || res.Data.Items[0].ThreadId == "" {
+		return nil, fmt.Errorf("feishu: message %s is not in a thread", rootId)
+	}
+	return c.ThreadMessages(ctx, res.Data.Items[0].ThreadId)
+}
+
+// listAllMessages 按创建时间顺序遍历容器中的全部消息，maxPages 大于 0 时最多请求 maxPages 页
+func (c *Client) listAllMessages(ctx context.Context, param ListMessagesParam, maxPages int, fn func(item MessageResDataItem) error) error {
+	param.SortType = SortByCreateTimeAsc
+	param.PageToken = ""
+	if param.PageSize <= 0 {
+		param.PageSize = 50
+	}
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		res, err := c.ListMessages(param)
+		if err != nil {
+			return err
+		}
+		if res.Code != 0 {
+			return fmt.Errorf("list messages code %d msg %s", res.Code, res.Msg)
+		}
+		for _, item := range res.Data.Items {
+			if err = fn(item); err != nil {
+				return err
+			}
+		}
+		if !res.Data.HasMore || res.Data.PageToken == "" || page == maxPages {
+			return nil
+		}
+		param.PageToken = res.Data.PageToken
+	}
+}
@@ -0,0 +1,55 @@
+package feishu
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestThreads(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/open-apis/im/v1/messages/om_root":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"items":[{"message_id":"om_root","thread_id":"omt_1"}]}}`))
+		case q.Get("container_id_type") == "chat" && q.Get("page_token") == "":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"has_more":true,"page_token":"p2","items":[
+				{"message_id":"om_root","thread_id":"omt_1"},
+				{"message_id":"om_reply","root_id":"om_root","thread_id":"omt_1"},
+				{"message_id":"om_plain"}]}}`))
+		case q.Get("container_id_type") == "chat":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"items":[{"message_id":"om_root2","thread_id":"omt_2"}]}}`))
+		case q.Get("container_id_type") == "thread" && q.Get("container_id") == "omt_1":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"items":[
+				{"message_id":"om_root","thread_id":"omt_1"},
+				{"message_id":"om_reply","root_id":"om_root","thread_id":"omt_1"}]}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			_, _ = w.Write([]byte(`{"code":1}`))
+		}
+	}))
+
+	ctx := context.Background()
+	if _, err := client.ListThreads(ctx, ListThreadsParam{ChatId: "oc_1"}); err != ErrListThreadsUnbounded {
+		t.Errorf("ListThreads() unbounded error = %v", err)
+	}
+	threads, err := client.ListThreads(ctx, ListThreadsParam{ChatId: "oc_1", StartTime: "1600000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 2 || threads[0].MessageId != "om_root" || threads[1].ThreadId != "omt_2" {
+		t.Errorf("ListThreads() = %+v", threads)
+	}
+	threads, err = client.ListThreads(ctx, ListThreadsParam{ChatId: "oc_1", MaxPages: 1})
+	if err != nil || len(threads) != 1 {
+		t.Errorf("ListThreads() MaxPages = %+v, %v", threads, err)
+	}
+
+	items, err := client.ThreadMessagesByRoot(ctx, "om_root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[1].MessageId != "om_reply" {
+		t.Errorf("ThreadMessagesByRoot() = %+v", items)
+	}
+}
@@ -117,7 +117,6 @@ func (c *Client) UsersFindByDepartment(param UsersFindByDepartmentParam) (*Users
 	}
 	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/contact/v3/users/find_by_department?"+params.Encode(), nil)
 	AccessToken, err := c.TokenManager.GetAccessToken()
-	fmt.Println(AccessToken)
 	if err != nil {
 		return nil, err
 	}
@@ -132,3 +131,46 @@ func (c *Client) UsersFindByDepartment(param UsersFindByDepartmentParam) (*Users
 	}
 	return &data, err
 }
+
+//--------------------------------------------------------------------------------------------------------------------
+
+// GetUsersParam 获取单个用户信息的请求结构体
+type GetUsersParam struct {
+	UserId           string
+	UserIdType       string
+	DepartmentIdType string
+}
+
+// GetUsersRes 获取单个用户信息的响应结构体
+type GetUsersRes struct {
+	ResponseCode
+	Data struct {
+		User UsersFindByDepartmentResDataItem `json:"user"`
+	} `json:"data"`
+}
+
+// GetUsers 获取单个用户信息
+func (c *Client) GetUsers(param GetUsersParam) (*GetUsersRes, error) {
+	params := url.Values{}
+	if param.UserIdType != "" {
+		params.Add("user_id_type", param.UserIdType)
+	}
+	if param.DepartmentIdType != "" {
+		params.Add("department_id_type", param.DepartmentIdType)
+	}
+	request, _ := http.NewRequest(http.MethodGet, ServerUrl+"/open-apis/contact/v3/users/"+param.UserId+"?"+params.Encode(), nil)
+	AccessToken, err := c.TokenManager.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(request, AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var data GetUsersRes
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, err
+}
@@ -40,8 +40,8 @@ func AESDecrypt(base64CipherText string, key []byte) (unpadDecrypted []byte, err
 	if err != nil {
 		return
 	}
-	if len(ciphertext) < aes.BlockSize {
-		err = errors.New("len(crypt) < aes.BlockSize")
+	if len(ciphertext) < 2*aes.BlockSize || len(ciphertext)%aes.BlockSize != 0 {
+		err = errors.New("invalid ciphertext length")
 		return
 	}
 	cbc := cipher.NewCBCDecrypter(block, ciphertext[:aes.BlockSize])
@@ -49,6 +49,11 @@ func AESDecrypt(base64CipherText string, key []byte) (unpadDecrypted []byte, err
 	decrypted := make([]byte, len(ciphertext))
 	cbc.CryptBlocks(decrypted, ciphertext)
 
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		err = errors.New("invalid padding")
+		return
+	}
 	unpadDecrypted = PKCS5Trimming(decrypted)
 	return
 }
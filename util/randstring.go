@@ -16,14 +16,22 @@ package util
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 )
 
 const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
+// seededRand 全局随机源，每次调用重新播种会使同一纳秒内的调用得到相同的字符串
+var (
+	seededRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	seededRandMu sync.Mutex
+)
+
 // GetRandStringWithCharset 获取指定字符集 下 指定长度的随机字符串
 func GetRandStringWithCharset(length int, charset string) string {
-	seededRand := rand.New(rand.NewSource(time.Now().UnixNano()))
+	seededRandMu.Lock()
+	defer seededRandMu.Unlock()
 
 	b := make([]byte, length)
 	for i := range b {
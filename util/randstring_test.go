@@ -66,3 +66,14 @@ func TestGetRandStringWithCharset(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRandStringUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		got := GetRandString(16)
+		if seen[got] {
+			t.Fatalf("GetRandString() returned duplicate %s", got)
+		}
+		seen[got] = true
+	}
+}
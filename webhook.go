@@ -0,0 +1,200 @@
+package feishu
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 自定义机器人错误码
+const (
+	WebhookCodeParamInvalid   = 9499  // 请求参数错误
+	WebhookCodeRateLimited    = 11232 // 发送频率超限
+	WebhookCodeSignMismatch   = 19021 // 签名校验失败
+	WebhookCodeIpNotAllowed   = 19022 // IP 不在白名单
+	WebhookCodeKeywordMissing = 19024 // 未包含关键词
+)
+
+// WebhookError 自定义机器人返回的错误
+type WebhookError struct {
+	Code int64
+	Msg  string
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("feishu: webhook code %d msg %s", e.Code, e.Msg)
+}
+
+// webhookStatusError 非 200 或无法解析的响应
+type webhookStatusError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("response.Status %s, response.Body %s", e.Status, e.Body)
+}
+
+// WebhookBot 群自定义机器人
+//
+// 无需应用凭证，设置了签名校验时需提供 Secret
+type WebhookBot struct {
+	WebhookUrl string
+	Secret     string
+	HttpClient *http.Client
+	MaxRetries int                             // 频率超限、服务端错误或连接失败时的最大重试次数
+	Backoff    func(attempt int) time.Duration // 第 attempt 次重试前的等待时间，默认指数退避
+}
+
+// NewWebhookBot 创建自定义机器人，webhookUrl 形如 https://open.feishu.cn/open-apis/bot/v2/hook/xxx
+func NewWebhookBot(webhookUrl, secret string) *WebhookBot {
+	return &WebhookBot{
+		WebhookUrl: webhookUrl,
+		Secret:     secret,
+		HttpClient: http.DefaultClient,
+		MaxRetries: 2,
+		Backoff:    defaultBackoff,
+	}
+}
+
+// WebhookMessage 自定义机器人消息体
+type WebhookMessage struct {
+	Timestamp string      `json:"timestamp,omitempty"`
+	Sign      string      `json:"sign,omitempty"`
+	MsgType   string      `json:"msg_type"`
+	Content   interface{} `json:"content,omitempty"`
+	Card      *Card       `json:"card,omitempty"`
+}
+
+// WebhookSign 计算签名，timestamp 为秒级时间戳
+func WebhookSign(secret string, timestamp int64) (string, error) {
+	stringToSign := strconv.FormatInt(timestamp, 10) + "\n" + secret
+	h := hmac.New(sha256.New, []byte(stringToSign))
+	if _, err := h.Write(nil); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// Send 发送消息，支持 text、image、post、share_chat、interactive
+//
+// 自定义机器人不支持幂等键，请求可能已被接收的错误（如读取响应超时）不会重试，避免重复发送
+func (b *WebhookBot) Send(ctx context.Context, content MessageContent) error {
+	c, card, err := legacyContent(content)
+	if err != nil {
+		return err
+	}
+	message := WebhookMessage{
+		MsgType: content.MsgType(),
+		Content: c,
+		Card:    card,
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = b.send(ctx, message)
+		if err == nil || attempt >= b.MaxRetries || !webhookRetryable(err) {
+			return err
+		}
+		if Logger != nil {
+			Logger.Printf("webhook attempt %d error %s", attempt+1, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(b.Backoff(attempt)):
+		}
+	}
+}
+
+// SendText 发送文本消息
+func (b *WebhookBot) SendText(ctx context.Context, text string) error {
+	return b.Send(ctx, NewTextContent(text))
+}
+
+// SendCard 发送消息卡片
+func (b *WebhookBot) SendCard(ctx context.Context, card *Card) error {
+	return b.Send(ctx, card)
+}
+
+func (b *WebhookBot) send(ctx context.Context, message WebhookMessage) error {
+	// 每次发送重新签名，避免重试时时间戳过期
+	if b.Secret != "" {
+		timestamp := time.Now().Unix()
+		sign, err := WebhookSign(b.Secret, timestamp)
+		if err != nil {
+			return err
+		}
+		message.Timestamp = strconv.FormatInt(timestamp, 10)
+		message.Sign = sign
+	}
+
+	jsonStr, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, b.WebhookUrl, strings.NewReader(string(jsonStr)))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentTypeApplicationJson)
+	request.Header.Set("User-Agent", UserAgent)
+
+	if Logger != nil {
+		Logger.Printf("%s %s", request.Method, request.URL.String())
+	}
+	response, err := b.HttpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	resp, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	// 新版返回 code/msg，旧版返回 StatusCode/StatusMessage
+	var data struct {
+		Code          int64  `json:"code"`
+		Msg           string `json:"msg"`
+		StatusCode    int64  `json:"StatusCode"`
+		StatusMessage string `json:"StatusMessage"`
+	}
+	if err = json.Unmarshal(resp, &data); err != nil {
+		return &webhookStatusError{StatusCode: response.StatusCode, Status: response.Status, Body: resp}
+	}
+	if data.Code != 0 {
+		return &WebhookError{Code: data.Code, Msg: data.Msg}
+	}
+	if data.StatusCode != 0 {
+		return &WebhookError{Code: data.StatusCode, Msg: data.StatusMessage}
+	}
+	if response.StatusCode != http.StatusOK {
+		return &webhookStatusError{StatusCode: response.StatusCode, Status: response.Status, Body: resp}
+	}
+	return nil
+}
+
+// webhookRetryable 只重试确定未发送成功的请求：频率超限、服务端错误与发送请求前的连接失败
+func webhookRetryable(err error) bool {
+	var webhookErr *WebhookError
+	if errors.As(err, &webhookErr) {
+		return webhookErr.Code == WebhookCodeRateLimited
+	}
+	var statusErr *webhookStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,84 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookBot(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		var message struct {
+			Timestamp string          `json:"timestamp"`
+			Sign      string          `json:"sign"`
+			MsgType   string          `json:"msg_type"`
+			Content   json.RawMessage `json:"content"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&message)
+
+		timestamp, _ := strconv.ParseInt(message.Timestamp, 10, 64)
+		sign, _ := WebhookSign("secret", timestamp)
+		switch {
+		case message.Sign != sign:
+			_, _ = w.Write([]byte(`{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`))
+		case attempts == 1:
+			_, _ = w.Write([]byte(`{"code":11232,"msg":"frequency limited"}`))
+		case message.MsgType != MsgTypeText || string(message.Content) != `{"text":"hi"}`:
+			_, _ = w.Write([]byte(`{"code":9499,"msg":"Bad Request"}`))
+		default:
+			_, _ = w.Write([]byte(`{"StatusCode":0,"StatusMessage":"success"}`))
+		}
+	}))
+	defer server.Close()
+
+	bot := NewWebhookBot(server.URL, "secret")
+	bot.Backoff = func(int) time.Duration { return time.Millisecond }
+	if err := bot.SendText(context.Background(), "hi"); err != nil || attempts != 2 {
+		t.Fatalf("SendText() error = %v, attempts = %d", err, attempts)
+	}
+
+	bot.Secret = "wrong"
+	var werr *WebhookError
+	if err := bot.SendText(context.Background(), "hi"); !errors.As(err, &werr) || werr.Code != WebhookCodeSignMismatch {
+		t.Errorf("SendText() error = %v", err)
+	}
+}
+
+func TestWebhookBotRetry(t *testing.T) {
+	var attempts int
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("error"))
+	}))
+	defer server.Close()
+
+	bot := NewWebhookBot(server.URL, "")
+	bot.MaxRetries = 2
+	bot.Backoff = func(int) time.Duration { return time.Millisecond }
+	if err := bot.SendText(context.Background(), "hi"); err == nil || attempts != 1 {
+		t.Errorf("4xx: error = %v, attempts = %d", err, attempts)
+	}
+
+	attempts = 0
+	status = http.StatusServiceUnavailable
+	if err := bot.SendText(context.Background(), "hi"); err == nil || attempts != 3 {
+		t.Errorf("5xx: error = %v, attempts = %d", err, attempts)
+	}
+
+	attempts = 0
+	bot.Backoff = func(int) time.Duration { return time.Hour }
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bot.SendText(ctx, "hi"); err != context.DeadlineExceeded || attempts != 1 {
+		t.Errorf("canceled: error = %v, attempts = %d", err, attempts)
+	}
+}
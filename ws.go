@@ -0,0 +1,400 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WSClientConfig 长连接配置，由飞书在获取连接地址以及 pong 帧中下发
+type WSClientConfig struct {
+	ReconnectCount    int `json:"ReconnectCount"`    // 最大重连次数，-1 表示不限
+	ReconnectInterval int `json:"ReconnectInterval"` // 重连间隔，单位秒
+	ReconnectNonce    int `json:"ReconnectNonce"`    // 首次重连的随机抖动，单位秒
+	PingInterval      int `json:"PingInterval"`      // 心跳间隔，单位秒
+}
+
+// 分片与读超时限制
+const (
+	wsReadTimeoutPings = 3 // 连续 3 个心跳间隔未收到任何帧时视为连接断开
+
+	wsMaxFragments    = 64              // 单条消息的最大分片数
+	wsFragmentTimeout = 5 * time.Second // 分片未收齐时的最长等待时间，超时丢弃
+)
+
+// wsFragments 未收齐的分片
+type wsFragments struct {
+	parts    [][]byte
+	deadline time.Time
+}
+
+type wsEndpointRes struct {
+	Code int64  `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		URL          string         `json:"URL"`
+		ClientConfig WSClientConfig `json:"ClientConfig"`
+	} `json:"data"`
+}
+
+// WSClient 长连接事件接收客户端
+//
+// 无需公网回调地址，收到的事件交给 Sink 处理，与 EventHandler 共用同一套分发逻辑；
+// 卡片交互交给 CardHandler 处理，与 CardActionHandler 的 http 回调一致。
+// 数据帧在独立的 goroutine 中处理，不阻塞心跳，Sink 与 CardHandler 需要支持并发调用；
+// 事件需要在 3 秒内应答，处理较慢时 Sink 应使用 AsyncDispatcher
+type WSClient struct {
+	AppId       string
+	AppSecret   string
+	Sink        EventSink
+	CardHandler *CardActionHandler // 可选，处理卡片交互回调
+	HttpClient  *http.Client
+	Dialer      *websocket.Dialer
+	Backoff     func(attempt int) time.Duration // 第 attempt 次重连前的等待时间，默认指数退避，不超过 ReconnectInterval
+
+	configLock sync.RWMutex
+	config     WSClientConfig
+	serviceId  int32
+
+	writeLock sync.Mutex
+	conn      *websocket.Conn
+	fragments map[string]*wsFragments
+}
+
+// NewWSClient 创建长连接客户端
+func NewWSClient(AppId, AppSecret string, sink EventSink) *WSClient {
+	return &WSClient{
+		AppId:      AppId,
+		AppSecret:  AppSecret,
+		Sink:       sink,
+		HttpClient: http.DefaultClient,
+		Dialer:     websocket.DefaultDialer,
+		Backoff:    defaultBackoff,
+		config: WSClientConfig{
+			ReconnectCount: -1,
+			PingInterval:   120,
+		},
+	}
+}
+
+// Start 建立长连接并持续接收事件，断线后自动重连
+//
+// 阻塞直到 ctx 结束或重连次数耗尽
+func (c *WSClient) Start(ctx context.Context) error {
+	attempt := 0
+	for {
+		connected, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if connected {
+			attempt = 0
+		}
+		if Logger != nil {
+			Logger.Printf("ws disconnected: %v", err)
+		}
+
+		config := c.getConfig()
+		if config.ReconnectCount >= 0 && attempt >= config.ReconnectCount {
+			return err
+		}
+
+		wait := c.Backoff(attempt)
+		if interval := time.Duration(config.ReconnectInterval) * time.Second; interval > 0 && wait > interval {
+			wait = interval
+		}
+		if attempt == 0 && config.ReconnectNonce > 0 {
+			wait += time.Duration(rand.Intn(config.ReconnectNonce*1000)) * time.Millisecond
+		}
+		attempt++
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// connect 建立一次连接并阻塞直到断开，connected 表示是否连接成功过
+func (c *WSClient) connect(ctx context.Context) (connected bool, err error) {
+	endpoint, err := c.endpoint(ctx)
+	if err != nil {
+		return
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return
+	}
+	serviceId, _ := strconv.ParseInt(u.Query().Get("service_id"), 10, 32)
+	c.serviceId = int32(serviceId)
+
+	conn, _, err := c.Dialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		return
+	}
+	connected = true
+	defer conn.Close()
+
+	c.writeLock.Lock()
+	c.conn = conn
+	c.writeLock.Unlock()
+	c.fragments = map[string]*wsFragments{}
+
+	if Logger != nil {
+		Logger.Printf("ws connected %s", u.Host)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go c.keepalive(ctx, conn, done)
+
+	for {
+		// 每收到一帧刷新读超时，半开连接上收不到 pong 时超时重连
+		timeout := time.Duration(wsReadTimeoutPings*c.getConfig().PingInterval) * time.Second
+		if err = conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return
+		}
+
+		var msgType int
+		var data []byte
+		msgType, data, err = conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if msgType != websocket.BinaryMessage {
+			continue
+		}
+
+		var frame wsFrame
+		if err = frame.unmarshal(data); err != nil {
+			return
+		}
+		c.handleFrame(ctx, &frame)
+	}
+}
+
+// endpoint 获取长连接地址
+func (c *WSClient) endpoint(ctx context.Context) (string, error) {
+	payload, _ := json.Marshal(map[string]string{
+		"AppID":     c.AppId,
+		"AppSecret": c.AppSecret,
+	})
+	request, _ := http.NewRequest(http.MethodPost, ServerUrl+"/callback/ws/endpoint", strings.NewReader(string(payload)))
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", contentTypeApplicationJson)
+	request.Header.Set("locale", "zh")
+	request.Header.Set("User-Agent", UserAgent)
+
+	response, err := c.HttpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	resp, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("response.Status %s, response.Body %s", response.Status, resp)
+	}
+
+	var data wsEndpointRes
+	if err = json.Unmarshal(resp, &data); err != nil {
+		return "", err
+	}
+	if data.Code != 0 || data.Data.URL == "" {
+		return "", fmt.Errorf("ws endpoint code %d msg %s", data.Code, data.Msg)
+	}
+
+	c.applyConfig(data.Data.ClientConfig)
+	return data.Data.URL, nil
+}
+
+func (c *WSClient) applyConfig(config WSClientConfig) {
+	if config.PingInterval <= 0 {
+		return
+	}
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+	c.config = config
+}
+
+func (c *WSClient) getConfig() WSClientConfig {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+	return c.config
+}
+
+// keepalive 定时发送 ping 帧，ctx 结束时关闭连接以打断读取
+func (c *WSClient) keepalive(ctx context.Context, conn *websocket.Conn, done chan struct{}) {
+	for {
+		interval := time.Duration(c.getConfig().PingInterval) * time.Second
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			_ = conn.Close()
+			return
+		case <-time.After(interval):
+		}
+
+		ping := &wsFrame{
+			Service: c.serviceId,
+			Method:  wsMethodControl,
+			Headers: []wsHeader{{Key: "type", Value: "ping"}},
+		}
+		if err := c.write(ping); err != nil {
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+func (c *WSClient) write(frame *wsFrame) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	return c.conn.WriteMessage(websocket.BinaryMessage, frame.marshal())
+}
+
+// handleFrame 在读取 goroutine 中处理控制帧与分片合并，数据帧交给新的 goroutine 处理
+func (c *WSClient) handleFrame(ctx context.Context, frame *wsFrame) {
+	if frame.Method == wsMethodControl {
+		if frame.header("type") == "pong" && len(frame.Payload) > 0 {
+			var config WSClientConfig
+			if err := json.Unmarshal(frame.Payload, &config); err == nil {
+				c.applyConfig(config)
+			}
+		}
+		return
+	}
+
+	payload := c.combine(frame)
+	if payload == nil {
+		return
+	}
+	go c.handleData(ctx, frame, payload)
+}
+
+// handleData 处理数据帧并应答
+func (c *WSClient) handleData(ctx context.Context, frame *wsFrame, payload []byte) {
+	start := time.Now()
+	code := http.StatusOK
+	data, err := c.dispatch(ctx, frame.header("type"), payload)
+	if err != nil {
+		if Logger != nil {
+			Logger.Printf("ws dispatch %s error %s", frame.header("message_id"), err)
+		}
+		code = http.StatusInternalServerError
+	}
+
+	ack := *frame
+	ack.Headers = append([]wsHeader(nil), frame.Headers...)
+	ack.setHeader("biz_rt", strconv.FormatInt(int64(time.Since(start)/time.Millisecond), 10))
+	ack.Payload, _ = json.Marshal(struct {
+		Code int    `json:"code"`
+		Data []byte `json:"data,omitempty"`
+	}{code, data})
+	if err = c.write(&ack); err != nil && Logger != nil {
+		Logger.Printf("ws ack %s error %s", frame.header("message_id"), err)
+	}
+}
+
+// dispatch 将数据帧交给 Sink 或 CardHandler 处理，data 为需要回传给飞书的响应
+func (c *WSClient) dispatch(ctx context.Context, frameType string, payload []byte) (data []byte, err error) {
+	switch frameType {
+	case "event":
+		var event *Event
+		event, _, err = ParseEvent(nil, payload)
+		if err != nil || event == nil {
+			return
+		}
+		err = c.Sink.Dispatch(ctx, event)
+	case "card":
+		if c.CardHandler == nil {
+			return
+		}
+		var action *CardAction
+		action, _, err = ParseCardAction(nil, payload)
+		if err != nil || action == nil {
+			return
+		}
+		var res *CardActionResponse
+		res, err = c.CardHandler.Dispatch(ctx, action)
+		if err != nil {
+			return
+		}
+		data, err = MarshalCardActionResponse(action, res)
+	default:
+		if Logger != nil {
+			Logger.Printf("ws unsupported frame type %s", frameType)
+		}
+	}
+	return
+}
+
+// combine 合并分片的数据帧，分片未收齐或分片数非法时返回 nil
+func (c *WSClient) combine(frame *wsFrame) []byte {
+	if frame.header("sum") == "" {
+		return frame.Payload
+	}
+	id := frame.header("message_id")
+	sum, err := strconv.Atoi(frame.header("sum"))
+	if err != nil || sum <= 0 || sum > wsMaxFragments {
+		if Logger != nil {
+			Logger.Printf("ws drop message %s with invalid sum %s", id, frame.header("sum"))
+		}
+		return nil
+	}
+	if sum == 1 {
+		return frame.Payload
+	}
+
+	now := time.Now()
+	for k, f := range c.fragments {
+		if now.After(f.deadline) {
+			if Logger != nil {
+				Logger.Printf("ws drop incomplete message %s", k)
+			}
+			delete(c.fragments, k)
+		}
+	}
+
+	seq, err := strconv.Atoi(frame.header("seq"))
+	if err != nil || seq < 0 || seq >= sum {
+		if Logger != nil {
+			Logger.Printf("ws drop message %s with invalid seq %s", id, frame.header("seq"))
+		}
+		return nil
+	}
+	f, ok := c.fragments[id]
+	if !ok {
+		f = &wsFragments{parts: make([][]byte, sum), deadline: now.Add(wsFragmentTimeout)}
+		c.fragments[id] = f
+	}
+	if len(f.parts) != sum {
+		return nil
+	}
+	f.parts[seq] = frame.Payload
+
+	var payload []byte
+	for _, p := range f.parts {
+		if p == nil {
+			return nil
+		}
+		payload = append(payload, p...)
+	}
+	delete(c.fragments, id)
+	return payload
+}
@@ -0,0 +1,170 @@
+package feishu
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// 长连接帧类型
+const (
+	wsMethodControl int32 = 0
+	wsMethodData    int32 = 1
+)
+
+// wsHeader 长连接帧头
+type wsHeader struct {
+	Key   string
+	Value string
+}
+
+// wsFrame 长连接帧，对应飞书 pbbp2.Frame 的 protobuf 编码
+type wsFrame struct {
+	SeqId           uint64
+	LogId           uint64
+	Service         int32
+	Method          int32
+	Headers         []wsHeader
+	PayloadEncoding string
+	PayloadType     string
+	Payload         []byte
+	LogIdNew        string
+}
+
+var errWsFrame = errors.New("feishu: malformed ws frame")
+
+// header 获取帧头
+func (f *wsFrame) header(key string) string {
+	for _, h := range f.Headers {
+		if h.Key == key {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// setHeader 设置帧头
+func (f *wsFrame) setHeader(key, value string) {
+	for i := range f.Headers {
+		if f.Headers[i].Key == key {
+			f.Headers[i].Value = value
+			return
+		}
+	}
+	f.Headers = append(f.Headers, wsHeader{Key: key, Value: value})
+}
+
+func (f *wsFrame) marshal() []byte {
+	var b []byte
+	b = appendVarintField(b, 1, f.SeqId)
+	b = appendVarintField(b, 2, f.LogId)
+	b = appendVarintField(b, 3, uint64(int64(f.Service)))
+	b = appendVarintField(b, 4, uint64(int64(f.Method)))
+	for _, h := range f.Headers {
+		var hb []byte
+		hb = appendBytesField(hb, 1, []byte(h.Key))
+		hb = appendBytesField(hb, 2, []byte(h.Value))
+		b = appendBytesField(b, 5, hb)
+	}
+	b = appendBytesField(b, 6, []byte(f.PayloadEncoding))
+	b = appendBytesField(b, 7, []byte(f.PayloadType))
+	b = appendBytesField(b, 8, f.Payload)
+	b = appendBytesField(b, 9, []byte(f.LogIdNew))
+	return b
+}
+
+func (f *wsFrame) unmarshal(b []byte) error {
+	return decodeFields(b, func(num int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			f.SeqId = v
+		case 2:
+			f.LogId = v
+		case 3:
+			f.Service = int32(v)
+		case 4:
+			f.Method = int32(v)
+		case 5:
+			var h wsHeader
+			err := decodeFields(data, func(num int, _ uint64, data []byte) error {
+				switch num {
+				case 1:
+					h.Key = string(data)
+				case 2:
+					h.Value = string(data)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			f.Headers = append(f.Headers, h)
+		case 6:
+			f.PayloadEncoding = string(data)
+		case 7:
+			f.PayloadType = string(data)
+		case 8:
+			f.Payload = append([]byte(nil), data...)
+		case 9:
+			f.LogIdNew = string(data)
+		}
+		return nil
+	})
+}
+
+// appendVarintField 写入 varint 字段，pbbp2 中的 varint 字段均为 required，零值也需要写入
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	b = appendUvarint(b, uint64(num)<<3)
+	return appendUvarint(b, v)
+}
+
+func appendBytesField(b []byte, num int, data []byte) []byte {
+	if len(data) == 0 {
+		return b
+	}
+	b = appendUvarint(b, uint64(num)<<3|2)
+	b = appendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// decodeFields 遍历 protobuf 字段，仅支持 varint 与 length-delimited 类型
+func decodeFields(b []byte, fn func(num int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errWsFrame
+		}
+		b = b[n:]
+
+		num := int(key >> 3)
+		switch key & 7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return errWsFrame
+			}
+			b = b[n:]
+			if err := fn(num, v, nil); err != nil {
+				return err
+			}
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errWsFrame
+			}
+			data := b[n : n+int(l)]
+			b = b[n+int(l):]
+			if err := fn(num, 0, data); err != nil {
+				return err
+			}
+		default:
+			return errWsFrame
+		}
+	}
+	return nil
+}
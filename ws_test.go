@@ -0,0 +1,222 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWsFrame(t *testing.T) {
+	frame := &wsFrame{
+		SeqId:   1,
+		Service: 42,
+		Method:  wsMethodData,
+		Headers: []wsHeader{{Key: "type", Value: "event"}, {Key: "message_id", Value: "m1"}},
+		Payload: []byte(`{"a":1}`),
+	}
+
+	var got wsFrame
+	if err := got.unmarshal(frame.marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if got.SeqId != 1 || got.Service != 42 || got.Method != wsMethodData || got.header("message_id") != "m1" || string(got.Payload) != `{"a":1}` {
+		t.Errorf("unmarshal() = %+v", got)
+	}
+}
+
+func TestWSClient(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var connections int32
+	acks := make(chan wsFrame, 2)
+	pings := make(chan struct{}, 10)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/callback/ws/endpoint", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["AppID"] != "cli_test" {
+			http.Error(w, "bad app", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"URL":"ws` + strings.TrimPrefix(server.URL, "http") + `/ws?service_id=7","ClientConfig":{"ReconnectCount":-1,"ReconnectInterval":1,"PingInterval":1}}}`))
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		n := atomic.AddInt32(&connections, 1)
+
+		// 分两片下发同一个事件
+		payload := []byte(`{"schema":"2.0","header":{"event_id":"e` + string(rune('0'+n)) + `","event_type":"im.message.receive_v1"},"event":{"message":{"chat_id":"oc_1"}}}`)
+		for seq, part := range [][]byte{payload[:10], payload[10:]} {
+			frame := &wsFrame{
+				Service: 7,
+				Method:  wsMethodData,
+				Headers: []wsHeader{{"type", "event"}, {"message_id", "m1"}, {"sum", "2"}, {"seq", string(rune('0' + seq))}},
+				Payload: part,
+			}
+			_ = conn.WriteMessage(websocket.BinaryMessage, frame.marshal())
+		}
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var frame wsFrame
+			_ = frame.unmarshal(data)
+			if frame.Method == wsMethodControl {
+				pings <- struct{}{}
+				continue
+			}
+			acks <- frame
+			if n == 1 {
+				// 首个连接收到应答后断开，验证重连
+				return
+			}
+		}
+	})
+
+	oldServerUrl := ServerUrl
+	ServerUrl = server.URL
+	defer func() { ServerUrl = oldServerUrl }()
+
+	received := make(chan string, 2)
+	dispatcher := NewEventDispatcher()
+	dispatcher.OnMessageReceive(func(ctx context.Context, event *Event, data *MessageReceiveEvent) error {
+		received <- event.Header.EventId
+		return nil
+	})
+
+	client := NewWSClient("cli_test", "secret", dispatcher)
+	client.Backoff = func(int) time.Duration { return 10 * time.Millisecond }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() { errc <- client.Start(ctx) }()
+
+	for _, want := range []string{"e1", "e2"} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Errorf("event_id = %s, want %s", got, want)
+			}
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for event")
+		}
+		ack := <-acks
+		if !strings.Contains(string(ack.Payload), `"code":200`) || ack.header("biz_rt") == "" {
+			t.Errorf("ack = %+v", ack)
+		}
+	}
+
+	select {
+	case <-pings:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for ping")
+	}
+
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("Start() error = %v", err)
+	}
+}
+
+func TestWSClientCombine(t *testing.T) {
+	c := &WSClient{fragments: map[string]*wsFragments{}}
+	fragment := func(id, sum, seq, payload string) *wsFrame {
+		return &wsFrame{
+			Headers: []wsHeader{{"message_id", id}, {"sum", sum}, {"seq", seq}},
+			Payload: []byte(payload),
+		}
+	}
+
+	if got := c.combine(&wsFrame{Payload: []byte("whole")}); string(got) != "whole" {
+		t.Errorf("combine() without sum = %s", got)
+	}
+	for _, sum := range []string{"0", "-1", "x", "100000000"} {
+		if got := c.combine(fragment("m0", sum, "0", "a")); got != nil || len(c.fragments) != 0 {
+			t.Errorf("combine() sum %s = %s, fragments = %d", sum, got, len(c.fragments))
+		}
+	}
+
+	for _, seq := range []string{"", "x", "-1", "2"} {
+		if got := c.combine(fragment("m0", "2", seq, "a")); got != nil || len(c.fragments) != 0 {
+			t.Errorf("combine() seq %q = %s, fragments = %d", seq, got, len(c.fragments))
+		}
+	}
+
+	if got := c.combine(fragment("m1", "2", "0", "a")); got != nil {
+		t.Errorf("combine() partial = %s", got)
+	}
+	c.fragments["m1"].deadline = time.Now().Add(-time.Second)
+	if got := c.combine(fragment("m2", "2", "1", "d")); got != nil {
+		t.Errorf("combine() partial = %s", got)
+	}
+	if _, ok := c.fragments["m1"]; ok || len(c.fragments) != 1 {
+		t.Errorf("expired fragments not evicted: %v", c.fragments)
+	}
+	if got := c.combine(fragment("m2", "2", "0", "c")); string(got) != "cd" || len(c.fragments) != 0 {
+		t.Errorf("combine() = %s, fragments = %d", got, len(c.fragments))
+	}
+}
+
+func TestWSClientReadTimeout(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var connections int32
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/callback/ws/endpoint", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":0,"data":{"URL":"ws` + strings.TrimPrefix(server.URL, "http") + `/ws","ClientConfig":{"ReconnectCount":-1,"ReconnectInterval":1,"PingInterval":1}}}`))
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		atomic.AddInt32(&connections, 1)
+		// 模拟半开连接：读取 ping 但从不应答
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	oldServerUrl := ServerUrl
+	ServerUrl = server.URL
+	defer func() { ServerUrl = oldServerUrl }()
+
+	client := NewWSClient("cli_test", "secret", NewEventDispatcher())
+	client.Backoff = func(int) time.Duration { return 10 * time.Millisecond }
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() { errc <- client.Start(ctx) }()
+
+	for atomic.LoadInt32(&connections) < 2 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("client did not reconnect after read timeout")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	cancel()
+	<-errc
+}